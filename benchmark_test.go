@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/mustafaturan/monoton/v3"
+	"github.com/mustafaturan/monoton/v3/encoder"
 	"github.com/mustafaturan/monoton/v3/sequencer"
 )
 
@@ -24,3 +25,32 @@ func BenchmarkNextBytes(b *testing.B) {
 		_ = m.NextBytes()
 	}
 }
+
+func BenchmarkNextBytesN(b *testing.B) {
+	b.ReportAllocs()
+
+	m, _ := monoton.New(sequencer.NewMillisecond(), 0, 0)
+	ids := make([][16]byte, 100)
+	for n := 0; n < b.N; n++ {
+		m.NextBytesN(ids)
+	}
+}
+
+func BenchmarkAppendNext(b *testing.B) {
+	b.ReportAllocs()
+
+	m, _ := monoton.New(sequencer.NewMillisecond(), 0, 0)
+	buf := make([]byte, 0, 16)
+	for n := 0; n < b.N; n++ {
+		buf = m.AppendNext(buf[:0])
+	}
+}
+
+func BenchmarkAppendBase62Padded(b *testing.B) {
+	b.ReportAllocs()
+
+	buf := make([]byte, 0, 11)
+	for n := 0; n < b.N; n++ {
+		buf = encoder.AppendBase62Padded(buf[:0], uint64(n), 11)
+	}
+}
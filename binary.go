@@ -0,0 +1,89 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package monoton
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+
+	"github.com/mustafaturan/monoton/v3/encoder"
+)
+
+// BinaryByteSize is the byte size of the binary representation of an ID
+const BinaryByteSize = 12
+
+const (
+	base62         = 62
+	errBinaryRange = "binary id %x exceeds the max identifier"
+)
+
+// BinaryRangeError is an error type with the binary value which can't be
+// represented as a 16 bytes Base62 identifier
+type BinaryRangeError struct {
+	Value []byte
+}
+
+func (e *BinaryRangeError) Error() string {
+	return fmt.Sprintf(errBinaryRange, e.Value)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The Base62
+// digits of the id are packed as one 96 bits big-endian integer, so the time,
+// the counter and the node keep their order under bytewise comparison. The
+// zero ID is encoded as an empty byte array.
+func (id ID) MarshalBinary() ([]byte, error) {
+	if id.IsZero() {
+		return []byte{}, nil
+	}
+
+	if err := encoder.ValidateBase62(id[:]); err != nil {
+		return nil, err
+	}
+
+	var hi, lo uint64
+	for i := range id {
+		d, _ := encoder.FromBase62(id[i : i+1])
+		carry, mul := bits.Mul64(lo, base62)
+		sum, c := bits.Add64(mul, d, 0)
+		hi, lo = hi*base62+carry+c, sum
+	}
+
+	b := make([]byte, BinaryByteSize)
+	binary.BigEndian.PutUint32(b, uint32(hi))
+	binary.BigEndian.PutUint64(b[4:], lo)
+	return b, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (id *ID) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 {
+		*id = ID{}
+		return nil
+	}
+	if len(b) != BinaryByteSize {
+		return &InvalidByteSizeError{
+			ByteSize:      len(b),
+			ByteSizeTotal: BinaryByteSize,
+		}
+	}
+
+	hi := uint64(binary.BigEndian.Uint32(b))
+	lo := binary.BigEndian.Uint64(b[4:])
+
+	var parsed ID
+	for i := totalByteSize - 1; i >= 0; i-- {
+		var r uint64
+		hi, r = hi/base62, hi%base62
+		lo, r = bits.Div64(r, lo, base62)
+		encoder.AppendBase62Padded(parsed[i:i], r, 1)
+	}
+	if hi != 0 || lo != 0 {
+		return &BinaryRangeError{Value: b}
+	}
+
+	*id = parsed
+	return nil
+}
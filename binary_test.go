@@ -0,0 +1,100 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package monoton
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/rand"
+	"testing"
+
+	"github.com/mustafaturan/monoton/v3/encoder"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"0000000000000000", "000000000000000000000000"},
+		{"000000G80001000J", "000000000307b32b527d6023"},
+		{"zzzzzzzzzzzzzzzz", "9a09afbae83050a9de00ffff"},
+	}
+
+	for _, test := range tests {
+		id, _ := ParseID(test.id)
+		b, err := id.MarshalBinary()
+		if err != nil || hex.EncodeToString(b) != test.want {
+			t.Errorf("MarshalBinary(%s) want: %s, got: %x, err: %v", test.id, test.want, b, err)
+		}
+
+		var got ID
+		if err := got.UnmarshalBinary(b); err != nil || got != id {
+			t.Errorf("UnmarshalBinary(%x) want: %s, got: %s, err: %v", b, id, got, err)
+		}
+	}
+
+	var zero ID
+	if b, err := zero.MarshalBinary(); err != nil || len(b) != 0 {
+		t.Errorf("MarshalBinary() of zero id want empty, got: %x, err: %v", b, err)
+	}
+
+	invalid := ID{'0', '0', '0', '-'}
+	want := "invalid char '-' at position 3"
+	if _, err := invalid.MarshalBinary(); err == nil || err.Error() != want {
+		t.Errorf("MarshalBinary(%s) want err: %s, got: %v", invalid, want, err)
+	}
+}
+
+func TestUnmarshalBinary(t *testing.T) {
+	id, _ := ParseID("000000G80001000J")
+	if err := id.UnmarshalBinary([]byte{}); err != nil || !id.IsZero() {
+		t.Errorf("UnmarshalBinary() want zero id, got: %s, err: %v", id, err)
+	}
+
+	tests := []struct {
+		b       string
+		wantErr string
+	}{
+		{"0000000000", "byte size must be 12 (given 5)"},
+		{"9a09afbae83050a9de010000", "binary id 9a09afbae83050a9de010000 exceeds the max identifier"},
+		{"ffffffffffffffffffffffff", "binary id ffffffffffffffffffffffff exceeds the max identifier"},
+	}
+
+	for _, test := range tests {
+		b, _ := hex.DecodeString(test.b)
+		var got ID
+		if err := got.UnmarshalBinary(b); err == nil || err.Error() != test.wantErr {
+			t.Errorf("UnmarshalBinary(%s) want err: %s, got: %v", test.b, test.wantErr, err)
+		}
+		if !got.IsZero() {
+			t.Errorf("UnmarshalBinary(%s) want zero id, got: %s", test.b, got)
+		}
+	}
+}
+
+func TestBinaryOrder(t *testing.T) {
+	const max8 = 218340105584896 // 62^8
+	r := rand.New(rand.NewSource(1))
+	random := func() ID {
+		var id ID
+		encoder.AppendBase62Padded(id[:0], r.Uint64()%max8, 8)
+		encoder.AppendBase62Padded(id[8:8], r.Uint64()%max8, 8)
+		return id
+	}
+
+	for i := 0; i < 10000; i++ {
+		a, b := random(), random()
+		if i%2 == 0 {
+			// share a prefix to compare the lower digits
+			copy(b[:r.Intn(16)], a[:])
+		}
+		ab, _ := a.MarshalBinary()
+		bb, _ := b.MarshalBinary()
+		if got, want := bytes.Compare(ab, bb), Compare(a, b); got != want {
+			t.Fatalf("bytes.Compare(%x, %x): %d, Compare(%s, %s): %d", ab, bb, got, a, b, want)
+		}
+	}
+}
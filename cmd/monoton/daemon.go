@@ -0,0 +1,46 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/mustafaturan/monoton/v3/daemon"
+)
+
+func runDaemon(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("daemon", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var (
+		gf     generatorFlags
+		socket string
+	)
+	gf.register(fs)
+	fs.StringVar(&socket, "socket", "/tmp/monoton.sock", "unix socket path")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	m, err := gf.monoton()
+	if err != nil {
+		return fail(stderr, err)
+	}
+	l, err := daemon.Listen(socket)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	ctx, stop := signalContext()
+	defer stop()
+
+	fmt.Fprintf(stdout, "listening on %s\n", l.Addr())
+	if err := daemon.NewServer(m).Serve(ctx, l); err != nil {
+		return fail(stderr, err)
+	}
+	return 0
+}
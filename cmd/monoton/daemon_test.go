@@ -0,0 +1,60 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mustafaturan/monoton/v3/daemon"
+)
+
+func TestDaemon(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func(f func() (context.Context, context.CancelFunc)) {
+		signalContext = f
+	}(signalContext)
+	signalContext = func() (context.Context, context.CancelFunc) {
+		return ctx, cancel
+	}
+
+	socket := filepath.Join(t.TempDir(), "monoton.sock")
+	r, w := io.Pipe()
+	var stderr bytes.Buffer
+	codes := make(chan int, 1)
+	go func() {
+		codes <- run([]string{"daemon", "-socket", socket, "-node", "19"}, w, &stderr)
+		w.Close()
+	}()
+
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil || line != "listening on "+socket+"\n" {
+		t.Fatalf("daemon want the listening address, got: %q, %v", line, err)
+	}
+
+	c, err := daemon.Dial(socket)
+	if err != nil {
+		t.Fatalf("daemon.Dial() returned error: %v", err)
+	}
+	defer c.Close()
+	if id, err := c.TryNext(); err != nil || !strings.HasSuffix(id, "000J") {
+		t.Errorf("TryNext() want an id with node 19, got: %q, %v", id, err)
+	}
+
+	cancel()
+	if code := <-codes; code != 0 {
+		t.Errorf("daemon want exit code 0, got: %d, %q", code, stderr.String())
+	}
+
+	code, _, errOut := runCommand("daemon", "-socket", filepath.Join(t.TempDir(), "missing", "m.sock"))
+	if code != 1 || !strings.HasPrefix(errOut, "monoton: listen unix") {
+		t.Errorf("daemon want listen error, got: %d, %q", code, errOut)
+	}
+}
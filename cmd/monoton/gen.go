@@ -0,0 +1,54 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+)
+
+var errCount = errors.New("count must be greater than 0")
+
+func runGen(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("gen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var (
+		gf     generatorFlags
+		n      int
+		format string
+	)
+	gf.register(fs)
+	fs.IntVar(&n, "n", 1, "number of identifiers")
+	fs.StringVar(&format, "format", formatText, "output format: text, json")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if err := validateFormat(format); err != nil {
+		return fail(stderr, err)
+	}
+	if n < 1 {
+		return fail(stderr, errCount)
+	}
+	m, err := gf.monoton()
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	ids := m.NextN(n)
+	if format == formatJSON {
+		if err := writeJSON(stdout, ids); err != nil {
+			return fail(stderr, err)
+		}
+		return 0
+	}
+	for _, id := range ids {
+		fmt.Fprintln(stdout, id)
+	}
+	return 0
+}
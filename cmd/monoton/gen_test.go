@@ -0,0 +1,61 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGen(t *testing.T) {
+	t.Run("prints one id per line", func(t *testing.T) {
+		code, stdout, stderr := runCommand("gen", "-node", "19", "-n", "3")
+		ids := strings.Fields(stdout)
+		if code != 0 || stderr != "" || len(ids) != 3 {
+			t.Fatalf("gen want 3 ids, got: %d, %q, %q", code, stdout, stderr)
+		}
+		for i, id := range ids {
+			if len(id) != 16 || !strings.HasSuffix(id, "000J") {
+				t.Errorf("gen want a 16 bytes id with node 19, got: %s", id)
+			}
+			if i > 0 && ids[i-1] >= id {
+				t.Errorf("gen want ordered ids, got: %s >= %s", ids[i-1], id)
+			}
+		}
+	})
+
+	t.Run("prints a JSON array", func(t *testing.T) {
+		code, stdout, _ := runCommand(
+			"gen",
+			"-sequencer", "second",
+			"-epoch", "2020-01-01T00:00:00Z",
+			"-n", "2",
+			"-format", "json",
+		)
+		var ids []string
+		if err := json.Unmarshal([]byte(stdout), &ids); code != 0 || err != nil || len(ids) != 2 {
+			t.Errorf("gen want 2 ids as JSON, got: %d, %q, %v", code, stdout, err)
+		}
+	})
+
+	errorTests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{[]string{"-n", "0"}, "monoton: count must be greater than 0"},
+		{[]string{"-sequencer", "hour"}, `monoton: sequencer must be one of second, millisecond, microsecond, nanosecond (given "hour")`},
+		{[]string{"-epoch", "yesterday"}, `monoton: epoch must be a time value or an RFC 3339 time (given "yesterday")`},
+		{[]string{"-node", "14776336"}, "monoton: node can't be greater than 14776335 (given 14776336)"},
+		{[]string{"-epoch", "99999999999999"}, "monoton: initial time can't be greater than the current time"},
+	}
+
+	for _, test := range errorTests {
+		code, _, stderr := runCommand(append([]string{"gen"}, test.args...)...)
+		if code != 1 || !strings.HasPrefix(stderr, test.wantErr) {
+			t.Errorf("gen %v want err: %s, got: %d, %q", test.args, test.wantErr, code, stderr)
+		}
+	}
+}
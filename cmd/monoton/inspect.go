@@ -0,0 +1,77 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3"
+)
+
+var errNoID = errors.New("at least one id is required")
+
+func runInspect(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var (
+		gf     generatorFlags
+		format string
+	)
+	gf.register(fs)
+	fs.StringVar(&format, "format", formatText, "output format: text, json")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if err := validateFormat(format); err != nil {
+		return fail(stderr, err)
+	}
+	if fs.NArg() == 0 {
+		return fail(stderr, errNoID)
+	}
+	m, err := gf.monoton()
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	inspections := make([]monoton.Inspection, fs.NArg())
+	for i, id := range fs.Args() {
+		inspection, err := m.Inspect(id)
+		if err != nil {
+			return fail(stderr, fmt.Errorf("%s: %w", id, err))
+		}
+		inspections[i] = inspection
+	}
+
+	if format == formatJSON {
+		if err := writeJSON(stdout, inspections); err != nil {
+			return fail(stderr, err)
+		}
+		return 0
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTIME\tCOUNTER\tNODE")
+	for _, i := range inspections {
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%d\t%d\n",
+			i.ID,
+			i.Time.Format(time.RFC3339Nano),
+			i.Counter,
+			i.Node,
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return fail(stderr, err)
+	}
+	return 0
+}
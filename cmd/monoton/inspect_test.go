@@ -0,0 +1,60 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3"
+)
+
+func TestInspect(t *testing.T) {
+	t.Run("prints a table", func(t *testing.T) {
+		code, stdout, stderr := runCommand("inspect", "000000G80001000J")
+		want := "ID                TIME                  COUNTER  NODE\n" +
+			"000000G80001000J  1970-01-01T00:00:01Z  1        19\n"
+		if code != 0 || stderr != "" || stdout != want {
+			t.Errorf("inspect want: %q, got: %d, %q, %q", want, code, stdout, stderr)
+		}
+	})
+
+	t.Run("prints the ids generated with the epoch", func(t *testing.T) {
+		_, id, _ := runCommand("gen", "-sequencer", "second", "-epoch", "2020-01-01T00:00:00Z", "-node", "7")
+		code, stdout, stderr := runCommand(
+			"inspect",
+			"-sequencer", "second",
+			"-epoch", "2020-01-01T00:00:00Z",
+			"-format", "json",
+			strings.TrimSpace(id),
+		)
+
+		var got []monoton.Inspection
+		if err := json.Unmarshal([]byte(stdout), &got); code != 0 || err != nil || len(got) != 1 {
+			t.Fatalf("inspect want JSON, got: %d, %q, %q, %v", code, stdout, stderr, err)
+		}
+		if got[0].Node != 7 || time.Since(got[0].Time) > time.Minute {
+			t.Errorf("inspect want node 7 and the current time, got: %+v", got[0])
+		}
+	})
+
+	errorTests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{nil, "monoton: at least one id is required"},
+		{[]string{"000000G8000-000J"}, "monoton: 000000G8000-000J: invalid char '-' at position 11"},
+		{[]string{"-format", "yaml", "000000G80001000J"}, `monoton: format must be text or json (given "yaml")`},
+	}
+
+	for _, test := range errorTests {
+		code, _, stderr := runCommand(append([]string{"inspect"}, test.args...)...)
+		if code != 1 || strings.TrimSpace(stderr) != test.wantErr {
+			t.Errorf("inspect %v want err: %s, got: %d, %q", test.args, test.wantErr, code, stderr)
+		}
+	}
+}
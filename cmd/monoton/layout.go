@@ -0,0 +1,101 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math/bits"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3/encoder"
+	"github.com/mustafaturan/monoton/v3/sequencer"
+)
+
+// layout is the byte split and the capacity of a sequencer
+type layout struct {
+	Sequencer    string `json:"sequencer"`
+	Unit         string `json:"unit"`
+	TimeBytes    int    `json:"time_bytes"`
+	CounterBytes int    `json:"counter_bytes"`
+	NodeBytes    int    `json:"node_bytes"`
+	MaxTime      uint64 `json:"max_time"`
+	MaxCounter   uint64 `json:"max_counter"`
+	MaxNode      uint64 `json:"max_node"`
+	// Until is the last time which can be represented without an epoch, it is
+	// formatted since JSON can't marshal the years after 9999
+	Until string `json:"until"`
+}
+
+func runLayout(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("layout", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var format string
+	fs.StringVar(&format, "format", formatText, "output format: text, json")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if err := validateFormat(format); err != nil {
+		return fail(stderr, err)
+	}
+
+	layouts := make([]layout, len(sequencers))
+	for i, s := range sequencers {
+		layouts[i] = newLayout(s.name, s.new())
+	}
+
+	if format == formatJSON {
+		if err := writeJSON(stdout, layouts); err != nil {
+			return fail(stderr, err)
+		}
+		return 0
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(
+		w,
+		"SEQUENCER\tUNIT\tTIME\tCOUNTER\tNODE\tMAX COUNTER\tMAX NODE\tUNTIL",
+	)
+	for _, l := range layouts {
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%d B\t%d B\t%d B\t%d\t%d\t%s\n",
+			l.Sequencer,
+			l.Unit,
+			l.TimeBytes,
+			l.CounterBytes,
+			l.NodeBytes,
+			l.MaxCounter,
+			l.MaxNode,
+			l.Until,
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return fail(stderr, err)
+	}
+	return 0
+}
+
+func newLayout(name string, s sequencer.Sequencer) layout {
+	unit := s.Unit()
+	hi, lo := bits.Mul64(s.MaxTime(), uint64(unit))
+	sec, nsec := bits.Div64(hi, lo, uint64(time.Second))
+
+	return layout{
+		Sequencer:    name,
+		Unit:         unit.String(),
+		TimeBytes:    encoder.Base62ByteSize(s.MaxTime()),
+		CounterBytes: encoder.Base62ByteSize(s.Max()),
+		NodeBytes:    encoder.Base62ByteSize(s.MaxNode()),
+		MaxTime:      s.MaxTime(),
+		MaxCounter:   s.Max(),
+		MaxNode:      s.MaxNode(),
+		Until: time.Unix(int64(sec), int64(nsec)).UTC().
+			Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLayout(t *testing.T) {
+	t.Run("prints a table", func(t *testing.T) {
+		code, stdout, _ := runCommand("layout")
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		if code != 0 || len(lines) != 5 {
+			t.Fatalf("layout want a header and 4 sequencers, got: %d, %q", code, stdout)
+		}
+		want := "millisecond  1ms   8 B   4 B      4 B   14776335     14776335  8888-12-02T13:19:44Z"
+		if lines[2] != want {
+			t.Errorf("layout want: %q, got: %q", want, lines[2])
+		}
+	})
+
+	t.Run("prints a JSON array", func(t *testing.T) {
+		code, stdout, _ := runCommand("layout", "-format", "json")
+		var got []layout
+		if err := json.Unmarshal([]byte(stdout), &got); code != 0 || err != nil {
+			t.Fatalf("layout want JSON, got: %d, %q, %v", code, stdout, err)
+		}
+
+		want := [][3]int{{6, 6, 4}, {8, 4, 4}, {10, 3, 3}, {11, 2, 3}}
+		for i, l := range got {
+			sizes := [3]int{l.TimeBytes, l.CounterBytes, l.NodeBytes}
+			if sizes != want[i] {
+				t.Errorf("layout of %s want: %v, got: %v", l.Sequencer, want[i], sizes)
+			}
+		}
+	})
+}
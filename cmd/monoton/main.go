@@ -0,0 +1,113 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+/*
+Command monoton generates and inspects monoton identifiers without writing Go.
+
+Usage:
+
+	monoton <command> [flags] [args]
+
+The commands are:
+
+	gen      generate identifiers
+	inspect  decode identifiers into time, counter and node
+	layout   print the byte layouts and capacities of the sequencers
+	serve    serve identifiers over HTTP
+	daemon   serve identifiers to the local processes over a unix socket
+
+The gen, inspect, serve and daemon commands accept the sequencer and the epoch
+(the initial time) flags, and the identifiers can only be inspected with the
+sequencer and the epoch they are generated with. The gen, inspect and layout
+commands print plain text by default and JSON with the -format json flag.
+
+Examples:
+
+	monoton gen -sequencer millisecond -node 19 -n 3
+	monoton inspect -format json 000000G80001000J
+	monoton layout
+	monoton serve -addr 127.0.0.1:8080 -node 19
+	monoton daemon -socket /run/monoton.sock -node 19
+*/
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+
+	errFormat = "format must be text or json (given %q)"
+	usage     = `usage: monoton <command> [flags] [args]
+
+The commands are:
+
+	gen      generate identifiers
+	inspect  decode identifiers into time, counter and node
+	layout   print the byte layouts and capacities of the sequencers
+	serve    serve identifiers over HTTP
+	daemon   serve identifiers to the local processes over a unix socket
+
+Run 'monoton <command> -h' for the flags of a command.
+`
+)
+
+// command runs a subcommand with its args and returns the exit code
+type command func(args []string, stdout, stderr io.Writer) int
+
+var commands = map[string]command{
+	"gen":     runGen,
+	"inspect": runInspect,
+	"layout":  runLayout,
+	"serve":   runServe,
+	"daemon":  runDaemon,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+			fmt.Fprint(stdout, usage)
+			return 0
+		}
+		fmt.Fprintf(stderr, "monoton: unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+	return cmd(args[1:], stdout, stderr)
+}
+
+// validateFormat returns an error for the unknown output formats
+func validateFormat(format string) error {
+	if format != formatText && format != formatJSON {
+		return fmt.Errorf(errFormat, format)
+	}
+	return nil
+}
+
+// writeJSON writes the value as indented JSON
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// fail prints the error and returns the exit code for the failures
+func fail(stderr io.Writer, err error) int {
+	fmt.Fprintf(stderr, "monoton: %v\n", err)
+	return 1
+}
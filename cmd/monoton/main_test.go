@@ -0,0 +1,46 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{nil, 2, "", "usage: monoton"},
+		{[]string{"help"}, 0, "usage: monoton", ""},
+		{[]string{"unknown"}, 2, "", `monoton: unknown command "unknown"`},
+		{[]string{"layout", "-format", "xml"}, 1, "", `monoton: format must be text or json (given "xml")`},
+		{[]string{"gen", "-unknown"}, 2, "", "flag provided but not defined: -unknown"},
+	}
+
+	for _, test := range tests {
+		code, stdout, stderr := runCommand(test.args...)
+		if code != test.wantCode ||
+			!strings.Contains(stdout, test.wantStdout) ||
+			!strings.Contains(stderr, test.wantStderr) {
+			t.Errorf(
+				"run(%v) want: %d, %q, %q, got: %d, %q, %q",
+				test.args,
+				test.wantCode, test.wantStdout, test.wantStderr,
+				code, stdout, stderr,
+			)
+		}
+	}
+}
+
+func runCommand(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
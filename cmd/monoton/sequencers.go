@@ -0,0 +1,99 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3"
+	"github.com/mustafaturan/monoton/v3/sequencer"
+)
+
+const (
+	errSequencer = "sequencer must be one of %s (given %q)"
+	errEpoch     = "epoch must be a time value or an RFC 3339 time (given %q)"
+)
+
+// sequencers are the built-in sequencers in the order of their units
+var sequencers = []struct {
+	name string
+	new  func(opts ...sequencer.Option) *sequencer.Sequence
+}{
+	{"second", sequencer.NewSecond},
+	{"millisecond", sequencer.NewMillisecond},
+	{"microsecond", sequencer.NewMicrosecond},
+	{"nanosecond", sequencer.NewNanosecond},
+}
+
+// generatorFlags are the flags to configure a monoton generator
+type generatorFlags struct {
+	sequencer string
+	node      uint64
+	epoch     string
+}
+
+func (f *generatorFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(
+		&f.sequencer,
+		"sequencer",
+		"millisecond",
+		"sequencer: "+sequencerNames(),
+	)
+	fs.Uint64Var(&f.node, "node", 0, "node identifier")
+	fs.StringVar(
+		&f.epoch,
+		"epoch",
+		"0",
+		"initial time as a time value in the sequencer unit or RFC 3339 time",
+	)
+}
+
+// monoton returns a generator with the configured flags
+func (f *generatorFlags) monoton() (monoton.Monoton, error) {
+	s, err := newSequencer(f.sequencer)
+	if err != nil {
+		return monoton.Monoton{}, err
+	}
+	initialTime, err := parseEpoch(f.epoch, s.Unit())
+	if err != nil {
+		return monoton.Monoton{}, err
+	}
+	return monoton.New(s, f.node, initialTime)
+}
+
+// newSequencer returns the built-in sequencer with the given name
+func newSequencer(name string) (*sequencer.Sequence, error) {
+	for _, s := range sequencers {
+		if s.name == name {
+			return s.new(), nil
+		}
+	}
+	return nil, fmt.Errorf(errSequencer, sequencerNames(), name)
+}
+
+func sequencerNames() string {
+	names := make([]string, len(sequencers))
+	for i, s := range sequencers {
+		names[i] = s.name
+	}
+	return strings.Join(names, ", ")
+}
+
+// parseEpoch converts the epoch into the time value in the given unit
+func parseEpoch(epoch string, unit time.Duration) (uint64, error) {
+	if v, err := strconv.ParseUint(epoch, 10, 64); err == nil {
+		return v, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, epoch)
+	if err != nil || t.Before(time.Unix(0, 0)) {
+		return 0, fmt.Errorf(errEpoch, epoch)
+	}
+	return uint64(t.Unix())*uint64(time.Second/unit) +
+		uint64(t.Nanosecond())/uint64(unit), nil
+}
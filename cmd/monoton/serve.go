@@ -0,0 +1,74 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3/server"
+)
+
+// signalContext returns the context which is done on the termination signals
+var signalContext = func() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(
+		context.Background(),
+		os.Interrupt,
+		syscall.SIGTERM,
+	)
+}
+
+func runServe(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var (
+		gf       generatorFlags
+		addr     string
+		maxBatch int
+		timeout  time.Duration
+	)
+	gf.register(fs)
+	fs.StringVar(&addr, "addr", "127.0.0.1:8080", "address to listen on")
+	fs.IntVar(&maxBatch, "max-batch", 1000, "max number of ids per request")
+	fs.DurationVar(
+		&timeout,
+		"shutdown-timeout",
+		5*time.Second,
+		"duration to wait for the active requests on shutdown",
+	)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if maxBatch < 1 {
+		return fail(stderr, errCount)
+	}
+	m, err := gf.monoton()
+	if err != nil {
+		return fail(stderr, err)
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	ctx, stop := signalContext()
+	defer stop()
+
+	fmt.Fprintf(stdout, "listening on %s\n", l.Addr())
+	h := server.New(m, server.WithMaxBatch(maxBatch))
+	if err := server.Serve(ctx, l, h, timeout); err != nil {
+		return fail(stderr, err)
+	}
+	return 0
+}
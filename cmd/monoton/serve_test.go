@@ -0,0 +1,69 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestServe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func(f func() (context.Context, context.CancelFunc)) {
+		signalContext = f
+	}(signalContext)
+	signalContext = func() (context.Context, context.CancelFunc) {
+		return ctx, cancel
+	}
+
+	r, w := io.Pipe()
+	var stderr bytes.Buffer
+	codes := make(chan int, 1)
+	go func() {
+		codes <- run([]string{"serve", "-addr", "127.0.0.1:0", "-node", "19"}, w, &stderr)
+		w.Close()
+	}()
+
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, "listening on ") {
+		t.Fatalf("serve want the listening address, got: %q, %v", line, err)
+	}
+	addr := strings.TrimSpace(strings.TrimPrefix(line, "listening on "))
+
+	res, err := http.Get("http://" + addr + "/id")
+	if err != nil {
+		t.Fatalf("GET /id returned error: %v", err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	if len(body) != 17 || !strings.HasSuffix(string(body), "000J\n") {
+		t.Errorf("GET /id want an id with node 19, got: %q", body)
+	}
+
+	cancel()
+	if code := <-codes; code != 0 {
+		t.Errorf("serve want exit code 0, got: %d, %q", code, stderr.String())
+	}
+
+	errorTests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{[]string{"-max-batch", "0"}, "monoton: count must be greater than 0"},
+		{[]string{"-addr", "127.0.0.1:-1"}, "monoton: listen tcp"},
+	}
+
+	for _, test := range errorTests {
+		code, _, stderr := runCommand(append([]string{"serve"}, test.args...)...)
+		if code != 1 || !strings.HasPrefix(stderr, test.wantErr) {
+			t.Errorf("serve %v want err: %s, got: %d, %q", test.args, test.wantErr, code, stderr)
+		}
+	}
+}
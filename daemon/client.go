@@ -0,0 +1,284 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package daemon
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3"
+)
+
+// ErrClosed is returned by the calls after the client is closed
+var ErrClosed = errors.New("daemon: client is closed")
+
+const (
+	defaultBlockSize = 256
+	defaultMaxAge    = time.Second
+	defaultTimeout   = 5 * time.Second
+)
+
+// Client generates identifiers by fetching them from the daemon in blocks. It
+// has the generation methods of monoton.Monoton, the Try methods return the
+// errors of the daemon and the connection while the others panic with them.
+// It is safe for concurrent use.
+type Client struct {
+	path      string
+	blockSize int
+	maxAge    time.Duration
+	timeout   time.Duration
+
+	mu      sync.Mutex
+	conn    net.Conn
+	r       *bufio.Reader
+	ids     [][idByteSize]byte
+	next    int
+	fetched time.Time
+	// err is the error of the daemon which is returned after the identifiers
+	// generated before it
+	err    error
+	closed bool
+}
+
+// ClientOption configures the optional behaviours of a Client
+type ClientOption func(*Client)
+
+// WithBlockSize sets the number of identifiers to prefetch with each request,
+// the default is 256
+func WithBlockSize(n int) ClientOption {
+	return func(c *Client) {
+		c.blockSize = n
+	}
+}
+
+// WithMaxAge sets the duration after which the prefetched identifiers are
+// dropped, zero keeps them until they are used. The default is 1 second.
+func WithMaxAge(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.maxAge = d
+	}
+}
+
+// WithTimeout sets the timeout of the connection and each request, the
+// default is 5 seconds
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// Dial connects to the daemon on the Unix domain socket
+func Dial(path string, opts ...ClientOption) (*Client, error) {
+	c := &Client{
+		path:      path,
+		blockSize: defaultBlockSize,
+		maxAge:    defaultMaxAge,
+		timeout:   defaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.blockSize < 1 || c.blockSize > maxBlockSize {
+		return nil, &BlockSizeError{
+			BlockSize:    c.blockSize,
+			MaxBlockSize: maxBlockSize,
+		}
+	}
+
+	if err := c.dial(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Close closes the connection, the prefetched identifiers are dropped and the
+// later calls return ErrClosed
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	c.ids, c.next = nil, 0
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// Next generates next incremental unique identifier as Base62, it panics when
+// the daemon fails, TryNext returns the errors instead
+func (c *Client) Next() string {
+	val := c.NextBytes()
+	return string(val[:])
+}
+
+// NextBytes generates next incremental unique identifier as Base62 16 bytes
+// array, it panics when the daemon fails
+func (c *Client) NextBytes() [16]byte {
+	id, err := c.TryNextBytes()
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// NextID generates next incremental unique identifier as monoton.ID, it
+// panics when the daemon fails
+func (c *Client) NextID() monoton.ID {
+	return monoton.ID(c.NextBytes())
+}
+
+// AppendNext appends the next incremental unique identifier as Base62 to dst
+// and returns the extended buffer, it panics when the daemon fails
+func (c *Client) AppendNext(dst []byte) []byte {
+	id := c.NextBytes()
+	return append(dst, id[:]...)
+}
+
+// NextN generates n incremental unique identifiers as Base62 which are
+// strictly ordered, it panics when the daemon fails
+func (c *Client) NextN(n int) []string {
+	ids := make([][idByteSize]byte, n)
+	c.NextBytesN(ids)
+
+	vals := make([]string, n)
+	for i := range ids {
+		vals[i] = string(ids[i][:])
+	}
+	return vals
+}
+
+// NextBytesN fills the dst with incremental unique identifiers as Base62 16
+// bytes arrays which are strictly ordered, it panics when the daemon fails
+func (c *Client) NextBytesN(dst [][16]byte) {
+	if _, err := c.TryNextBytesN(dst); err != nil {
+		panic(err)
+	}
+}
+
+// TryNext generates next incremental unique identifier as Base62 like Next,
+// but returns the errors of the daemon and the connection
+func (c *Client) TryNext() (string, error) {
+	val, err := c.TryNextBytes()
+	if err != nil {
+		return "", err
+	}
+	return string(val[:]), nil
+}
+
+// TryNextBytes generates next incremental unique identifier as Base62 16
+// bytes array or returns the errors
+func (c *Client) TryNextBytes() ([16]byte, error) {
+	var dst [1][idByteSize]byte
+	_, err := c.TryNextBytesN(dst[:])
+	return dst[0], err
+}
+
+// TryNextID generates next incremental unique identifier as monoton.ID or
+// returns the errors
+func (c *Client) TryNextID() (monoton.ID, error) {
+	val, err := c.TryNextBytes()
+	return monoton.ID(val), err
+}
+
+// TryNextBytesN fills the dst with incremental unique identifiers as Base62 16
+// bytes arrays which are strictly ordered like NextBytesN, but stops at the
+// first error and returns it with the count of the generated identifiers
+func (c *Client) TryNextBytesN(dst [][16]byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return 0, ErrClosed
+	}
+	if c.maxAge > 0 && time.Since(c.fetched) > c.maxAge {
+		c.ids, c.next = c.ids[:0], 0
+	}
+	for i := 0; i < len(dst); {
+		if c.next < len(c.ids) {
+			n := copy(dst[i:], c.ids[c.next:])
+			c.next += n
+			i += n
+			continue
+		}
+		if c.err != nil {
+			err := c.err
+			c.err = nil
+			return i, err
+		}
+
+		n := len(dst) - i
+		if n < c.blockSize {
+			n = c.blockSize
+		}
+		if n > maxBlockSize {
+			n = maxBlockSize
+		}
+		if err := c.fetch(n); err != nil {
+			return i, err
+		}
+	}
+	return len(dst), nil
+}
+
+// fetch replaces the prefetched identifiers with a new block, the connection
+// is redialed once when it is broken
+func (c *Client) fetch(n int) error {
+	var (
+		ids [][idByteSize]byte
+		err error
+	)
+	for attempt := 0; attempt < 2; attempt++ {
+		ids, err = c.roundTrip(n)
+		if _, remote := err.(*RemoteError); err == nil || remote {
+			break
+		}
+		if c.conn != nil {
+			c.conn.Close()
+			c.conn = nil
+		}
+	}
+
+	if _, remote := err.(*RemoteError); err != nil && !remote {
+		c.ids, c.next = c.ids[:0], 0
+		return err
+	}
+
+	c.ids, c.next, c.fetched = ids, 0, time.Now()
+	if err != nil && len(ids) == 0 {
+		return err
+	}
+	c.err = err
+	return nil
+}
+
+func (c *Client) roundTrip(n int) ([][idByteSize]byte, error) {
+	if c.conn == nil {
+		if err := c.dial(); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return nil, err
+	}
+	if err := writeRequest(c.conn, n); err != nil {
+		return nil, err
+	}
+	return readResponse(c.r, c.ids[:0])
+}
+
+func (c *Client) dial() error {
+	conn, err := net.DialTimeout("unix", c.path, c.timeout)
+	if err != nil {
+		return err
+	}
+	c.conn = conn
+	c.r = bufio.NewReader(conn)
+	return nil
+}
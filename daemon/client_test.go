@@ -0,0 +1,278 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3"
+	"github.com/mustafaturan/monoton/v3/internal/netserve"
+	"github.com/mustafaturan/monoton/v3/internal/servetest"
+	"github.com/mustafaturan/monoton/v3/mtimer"
+	"github.com/mustafaturan/monoton/v3/sequencer"
+)
+
+// generator is the generation method set of monoton.Monoton
+type generator interface {
+	Next() string
+	NextBytes() [16]byte
+	NextID() monoton.ID
+	AppendNext(dst []byte) []byte
+	NextN(n int) []string
+	NextBytesN(dst [][16]byte)
+	TryNext() (string, error)
+	TryNextBytes() ([16]byte, error)
+	TryNextID() (monoton.ID, error)
+	TryNextBytesN(dst [][16]byte) (int, error)
+}
+
+var (
+	_ generator = monoton.Monoton{}
+	_ generator = (*Client)(nil)
+)
+
+func TestDial(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.sock")
+	if _, err := Dial(path); err == nil {
+		t.Errorf("Dial(%s) want err, got nil", path)
+	}
+
+	want := "block size must be between 1 and 65536 (given 0)"
+	if _, err := Dial(path, WithBlockSize(0)); err == nil || err.Error() != want {
+		t.Errorf("Dial() want err: %s, got: %v", want, err)
+	}
+}
+
+func TestClient(t *testing.T) {
+	path, stop := startServer(t, nil)
+	defer stop()
+
+	c1, _ := Dial(path, WithBlockSize(4), WithMaxAge(0))
+	defer c1.Close()
+	c2, _ := Dial(path, WithBlockSize(4), WithMaxAge(0))
+	defer c2.Close()
+
+	t.Run("prefetches blocks", func(t *testing.T) {
+		want := []struct {
+			c  *Client
+			id string
+		}{
+			{c1, "000000G80000000J"},
+			{c2, "000000G80004000J"},
+			{c1, "000000G80001000J"},
+			{c2, "000000G80005000J"},
+		}
+		for _, w := range want {
+			if got, err := w.c.TryNext(); got != w.id || err != nil {
+				t.Errorf("TryNext() want: %s, got: %s, %v", w.id, got, err)
+			}
+		}
+	})
+
+	t.Run("uses the prefetched ids before the new blocks", func(t *testing.T) {
+		ids := make([][16]byte, 6)
+		if n, err := c1.TryNextBytesN(ids); n != 6 || err != nil {
+			t.Fatalf("TryNextBytesN() want: 6, <nil>, got: %d, %v", n, err)
+		}
+		want := []string{
+			"000000G80002000J", "000000G80003000J", "000000G80008000J",
+			"000000G80009000J", "000000G8000A000J", "000000G8000B000J",
+		}
+		for i := range want {
+			if got := string(ids[i][:]); got != want[i] {
+				t.Errorf("TryNextBytesN()[%d] want: %s, got: %s", i, want[i], got)
+			}
+		}
+	})
+
+	t.Run("drops the prefetched ids after the max age", func(t *testing.T) {
+		c, _ := Dial(path, WithBlockSize(4), WithMaxAge(time.Nanosecond))
+		defer c.Close()
+
+		first, _ := c.TryNextID()
+		time.Sleep(time.Millisecond)
+		c1.TryNext()
+		second, err := c.TryNextID()
+		if err != nil || !second.After(first) || second.String() != "000000G8000K000J" {
+			t.Errorf("TryNextID() want a new block after: %s, got: %s, %v", first, second, err)
+		}
+	})
+}
+
+func TestClientConcurrent(t *testing.T) {
+	path, stop := startServer(t, sequencer.NewMillisecond())
+	defer stop()
+
+	const clients, workers, ids = 4, 4, 500
+	var (
+		mu   sync.Mutex
+		seen = make(map[string]bool, clients*workers*ids)
+		wg   sync.WaitGroup
+	)
+	for i := 0; i < clients; i++ {
+		c, err := Dial(path, WithBlockSize(64))
+		if err != nil {
+			t.Fatalf("Dial() returned error: %v", err)
+		}
+		defer c.Close()
+
+		for j := 0; j < workers; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				prev := ""
+				for k := 0; k < ids; k++ {
+					id, err := c.TryNext()
+					if err != nil || id <= prev {
+						t.Errorf("TryNext() want an id greater than %s, got: %s, %v", prev, id, err)
+						return
+					}
+					prev = id
+
+					mu.Lock()
+					if seen[id] {
+						t.Errorf("TryNext() returned a duplicate id: %s", id)
+					}
+					seen[id] = true
+					mu.Unlock()
+				}
+			}()
+		}
+	}
+	wg.Wait()
+}
+
+func TestClientErrors(t *testing.T) {
+	t.Run("returns the errors of the sequencer after the generated ids", func(t *testing.T) {
+		s, _ := sequencer.NewCustom(sequencer.Config{
+			Unit:         time.Millisecond,
+			TimeBytes:    11,
+			CounterBytes: 1,
+			NodeBytes:    4,
+			Clock:        mtimer.NewFakeClock(time.Unix(1, 0)),
+		}, sequencer.WithOverflowPolicy(sequencer.OverflowFail))
+		path, stop := startServer(t, s)
+		defer stop()
+
+		c, _ := Dial(path, WithBlockSize(100))
+		defer c.Close()
+
+		ids := make([][16]byte, 70)
+		n, err := c.TryNextBytesN(ids)
+		if _, ok := err.(*RemoteError); !ok || n != 62 {
+			t.Fatalf("TryNextBytesN() want: 62, *RemoteError, got: %d, %v", n, err)
+		}
+		if got := string(ids[61][:]); got != "000000000G8z000J" {
+			t.Errorf("TryNextBytesN() want the last id before the error, got: %s", got)
+		}
+		if _, err := c.TryNext(); err == nil {
+			t.Errorf("TryNext() want error, got nil")
+		}
+
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Next() want panic, got nil")
+			}
+		}()
+		c.Next()
+	})
+
+	t.Run("returns the errors of the daemon after the generated ids", func(t *testing.T) {
+		path := startFakeServer(t, func(n int) ([][16]byte, error) {
+			ids := make([][16]byte, 62)
+			for i := range ids {
+				ids[i][0] = byte(i)
+			}
+			return ids, errors.New("max time exceeded")
+		})
+
+		c, _ := Dial(path, WithBlockSize(100))
+		defer c.Close()
+
+		ids := make([][16]byte, 70)
+		n, err := c.TryNextBytesN(ids)
+		if _, ok := err.(*RemoteError); !ok || n != 62 || err.Error() != "daemon: max time exceeded" {
+			t.Fatalf("TryNextBytesN() want: 62, *RemoteError, got: %d, %v", n, err)
+		}
+		if ids[61][0] != 61 {
+			t.Errorf("TryNextBytesN() want the last id before the error, got: %v", ids[61])
+		}
+	})
+
+	t.Run("errors after close", func(t *testing.T) {
+		path, stop := startServer(t, nil)
+		defer stop()
+		c, _ := Dial(path)
+		c.Close()
+
+		if _, err := c.TryNext(); err != ErrClosed {
+			t.Errorf("TryNext() after close want: %v, got: %v", ErrClosed, err)
+		}
+		if err := c.Close(); err != nil {
+			t.Errorf("Close() twice returned error: %v", err)
+		}
+	})
+
+	t.Run("redials after the daemon restarts", func(t *testing.T) {
+		path, stop := startServer(t, nil)
+		c, _ := Dial(path, WithBlockSize(1))
+		defer c.Close()
+		c.TryNext()
+		stop()
+
+		if _, err := c.TryNext(); err == nil {
+			t.Fatalf("TryNext() without the daemon want error, got nil")
+		}
+
+		l, err := Listen(path)
+		if err != nil {
+			t.Fatalf("Listen() returned error: %v", err)
+		}
+		m, _ := monoton.New(sequencer.NewMillisecond(), 19, 0)
+		go NewServer(m).Serve(context.Background(), l)
+		defer l.Close()
+
+		if _, err := c.TryNext(); err != nil {
+			t.Errorf("TryNext() after restart returned error: %v", err)
+		}
+	})
+}
+
+// startFakeServer serves the responses of the given function on a temporary
+// socket until the test ends
+func startFakeServer(
+	t *testing.T,
+	respond func(n int) ([][16]byte, error),
+) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "fake.sock")
+	l, err := Listen(path)
+	if err != nil {
+		t.Fatalf("Listen() returned error: %v", err)
+	}
+	srv := &netserve.Server{Handle: func(conn net.Conn) {
+		r, w := bufio.NewReader(conn), bufio.NewWriter(conn)
+		for {
+			n, err := readRequest(r)
+			if err != nil {
+				return
+			}
+			ids, err := respond(n)
+			if writeResponse(w, ids, err) != nil {
+				return
+			}
+		}
+	}}
+	t.Cleanup(servetest.Start(t, l, srv.Serve))
+	return path
+}
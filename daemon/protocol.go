@@ -0,0 +1,119 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package daemon
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// The request is the number of identifiers as a big-endian uint32. The
+// response is the number of generated identifiers as a big-endian uint32,
+// the 16 bytes identifiers and the error message with its big-endian uint16
+// length. The identifiers are generated until the first error.
+const (
+	idByteSize   = 16
+	maxBlockSize = 1 << 16
+	maxErrorSize = 1<<16 - 1
+
+	errBlockSize = "block size must be between 1 and %d (given %d)"
+	errRemote    = "daemon: %s"
+)
+
+// BlockSizeError is an error type with the requested number of identifiers
+// which is out of the limits
+type BlockSizeError struct {
+	BlockSize    int
+	MaxBlockSize int
+}
+
+func (e *BlockSizeError) Error() string {
+	return fmt.Sprintf(errBlockSize, e.MaxBlockSize, e.BlockSize)
+}
+
+// RemoteError is an error type with the message of the error which is
+// returned by the daemon
+type RemoteError struct {
+	Message string
+}
+
+func (e *RemoteError) Error() string {
+	return fmt.Sprintf(errRemote, e.Message)
+}
+
+func writeRequest(w io.Writer, n int) error {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(n))
+	_, err := w.Write(b[:])
+	return err
+}
+
+func readRequest(r io.Reader) (int, error) {
+	var b [4]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, err
+	}
+	return int(binary.BigEndian.Uint32(b[:])), nil
+}
+
+func writeResponse(w *bufio.Writer, ids [][idByteSize]byte, err error) error {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(len(ids)))
+	w.Write(b[:])
+	for i := range ids {
+		w.Write(ids[i][:])
+	}
+
+	var msg string
+	if err != nil {
+		msg = err.Error()
+		if len(msg) > maxErrorSize {
+			msg = msg[:maxErrorSize]
+		}
+	}
+	binary.BigEndian.PutUint16(b[:2], uint16(len(msg)))
+	w.Write(b[:2])
+	w.WriteString(msg)
+	return w.Flush()
+}
+
+// readResponse appends the identifiers of the response to dst, the error of
+// the daemon is returned as *RemoteError with the identifiers which are
+// generated before the error
+func readResponse(
+	r io.Reader,
+	dst [][idByteSize]byte,
+) ([][idByteSize]byte, error) {
+	var b [4]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return dst, err
+	}
+	n := int(binary.BigEndian.Uint32(b[:]))
+	if n > maxBlockSize {
+		return dst, &BlockSizeError{BlockSize: n, MaxBlockSize: maxBlockSize}
+	}
+
+	for i := 0; i < n; i++ {
+		var id [idByteSize]byte
+		if _, err := io.ReadFull(r, id[:]); err != nil {
+			return dst, err
+		}
+		dst = append(dst, id)
+	}
+
+	if _, err := io.ReadFull(r, b[:2]); err != nil {
+		return dst, err
+	}
+	msg := make([]byte, binary.BigEndian.Uint16(b[:2]))
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return dst, err
+	}
+	if len(msg) > 0 {
+		return dst, &RemoteError{Message: string(msg)}
+	}
+	return dst, nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+/*
+Package daemon provides a Unix domain socket server which hands out the
+identifiers of one monoton generator to the processes on the same host, and a
+client with the generation methods of monoton.Monoton. The processes share the
+node of the daemon, so the workers don't need distinct nodes.
+
+The daemon reserves the sequences of each block in ranges like
+monoton.TryNextBytesN and replies the identifiers which are generated before
+an error with the error, so the clients receive the errors of the sequencer.
+
+The client prefetches the identifiers in blocks to amortise the round-trips
+and drops the blocks which are older than the max age, so the identifiers of a
+client are strictly ordered and lag behind the clock at most by the max age.
+
+	// daemon
+	m, err := monoton.New(sequencer.NewMillisecond(), node, initialTime)
+	if err != nil {
+		panic(err)
+	}
+	l, err := daemon.Listen("/run/monoton.sock")
+	if err != nil {
+		panic(err)
+	}
+	err = daemon.NewServer(m).Serve(ctx, l)
+
+	// workers
+	c, err := daemon.Dial("/run/monoton.sock", daemon.WithBlockSize(1024))
+	if err != nil {
+		panic(err)
+	}
+	defer c.Close()
+	id, err := c.TryNext()
+*/
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/mustafaturan/monoton/v3"
+	"github.com/mustafaturan/monoton/v3/internal/netserve"
+)
+
+const errAddrInUse = "socket %s is in use"
+
+// AddrInUseError is an error type with the path of the socket which is served
+// by another process
+type AddrInUseError struct {
+	Path string
+}
+
+func (e *AddrInUseError) Error() string {
+	return fmt.Sprintf(errAddrInUse, e.Path)
+}
+
+// Server is a daemon which serves the identifiers of a generator
+type Server struct {
+	monoton monoton.Monoton
+}
+
+// NewServer returns a Server which generates the identifiers with the given
+// monoton
+func NewServer(m monoton.Monoton) *Server {
+	return &Server{monoton: m}
+}
+
+// Listen listens on the Unix domain socket, the stale socket file of a crashed
+// daemon is removed while the socket of a running daemon is not
+func Listen(path string) (net.Listener, error) {
+	l, err := net.Listen("unix", path)
+	if err == nil {
+		return l, nil
+	}
+
+	fi, statErr := os.Stat(path)
+	if statErr != nil || fi.Mode()&os.ModeSocket == 0 {
+		return nil, err
+	}
+	if conn, dialErr := net.Dial("unix", path); dialErr == nil {
+		conn.Close()
+		return nil, &AddrInUseError{Path: path}
+	}
+	if err := os.Remove(path); err != nil {
+		return nil, err
+	}
+	return net.Listen("unix", path)
+}
+
+// Serve accepts the connections on the listener until the context is done,
+// then closes the listener and waits the connections to reply the requests
+// which are already read
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	srv := &netserve.Server{Handle: s.handle}
+	return srv.Serve(ctx, l)
+}
+
+func (s *Server) handle(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	ids := make([][idByteSize]byte, 0, 64)
+
+	for {
+		n, err := readRequest(r)
+		if err != nil {
+			return
+		}
+		if n < 1 || n > maxBlockSize {
+			err := &BlockSizeError{BlockSize: n, MaxBlockSize: maxBlockSize}
+			if writeResponse(w, nil, err) != nil {
+				return
+			}
+			continue
+		}
+
+		ids, err = s.generate(ids[:0], n)
+		if writeResponse(w, ids, err) != nil {
+			return
+		}
+	}
+}
+
+// generate appends n identifiers to dst until the first error, the sequences
+// are reserved in ranges like monoton.TryNextBytesN
+func (s *Server) generate(
+	dst [][idByteSize]byte,
+	n int,
+) ([][idByteSize]byte, error) {
+	dst = append(dst, make([][idByteSize]byte, n)...)
+	generated, err := s.monoton.TryNextBytesN(dst)
+	return dst[:generated], err
+}
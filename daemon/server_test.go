@@ -0,0 +1,125 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package daemon
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3"
+	"github.com/mustafaturan/monoton/v3/internal/servetest"
+	"github.com/mustafaturan/monoton/v3/mtimer"
+	"github.com/mustafaturan/monoton/v3/sequencer"
+)
+
+func TestListen(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("removes the stale socket", func(t *testing.T) {
+		path := filepath.Join(dir, "stale.sock")
+		stale, _ := net.Listen("unix", path)
+		stale.(*net.UnixListener).SetUnlinkOnClose(false)
+		stale.Close()
+
+		l, err := Listen(path)
+		if err != nil {
+			t.Fatalf("Listen(%s) returned error: %v", path, err)
+		}
+		l.Close()
+	})
+
+	t.Run("keeps the socket of a running daemon", func(t *testing.T) {
+		path := filepath.Join(dir, "running.sock")
+		running, _ := net.Listen("unix", path)
+		defer running.Close()
+
+		want := "socket " + path + " is in use"
+		if _, err := Listen(path); err == nil || err.Error() != want {
+			t.Errorf("Listen(%s) want err: %s, got: %v", path, want, err)
+		}
+	})
+
+	t.Run("keeps the other files", func(t *testing.T) {
+		path := filepath.Join(dir, "file")
+		os.WriteFile(path, nil, 0o600)
+
+		if _, err := Listen(path); err == nil {
+			t.Errorf("Listen(%s) want err, got nil", path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Listen(%s) removed the file: %v", path, err)
+		}
+	})
+}
+
+func TestServer(t *testing.T) {
+	path, stop := startServer(t, nil)
+	defer stop()
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("net.Dial() returned error: %v", err)
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	writeRequest(conn, 2)
+	ids, err := readResponse(r, nil)
+	if err != nil || len(ids) != 2 {
+		t.Fatalf("readResponse() want 2 ids, got: %q, %v", ids, err)
+	}
+	if string(ids[0][:]) != "000000G80000000J" || string(ids[1][:]) != "000000G80001000J" {
+		t.Errorf("readResponse() got: %s, %s", ids[0][:], ids[1][:])
+	}
+
+	for _, n := range []int{0, maxBlockSize + 1} {
+		writeRequest(conn, n)
+		_, err := readResponse(r, nil)
+		if _, ok := err.(*RemoteError); !ok {
+			t.Errorf("request of %d ids want *RemoteError, got: %v", n, err)
+		}
+	}
+}
+
+func TestServerShutdown(t *testing.T) {
+	path, stop := startServer(t, nil)
+
+	c, err := Dial(path)
+	if err != nil {
+		t.Fatalf("Dial() returned error: %v", err)
+	}
+	defer c.Close()
+	if _, err := c.TryNext(); err != nil {
+		t.Fatalf("TryNext() returned error: %v", err)
+	}
+
+	stop()
+	if _, err := net.Dial("unix", path); err == nil {
+		t.Errorf("Dial() after shutdown want error, got nil")
+	}
+}
+
+// startServer serves a generator with a fake clock on a temporary socket and
+// returns the path with a function which stops the server
+func startServer(t *testing.T, s sequencer.Sequencer) (string, func()) {
+	t.Helper()
+
+	if s == nil {
+		clock := mtimer.NewFakeClock(time.Unix(1, 0))
+		s = sequencer.NewMillisecond(sequencer.WithClock(clock))
+	}
+	m, _ := monoton.New(s, 19, 0)
+
+	path := filepath.Join(t.TempDir(), "monoton.sock")
+	l, err := Listen(path)
+	if err != nil {
+		t.Fatalf("Listen() returned error: %v", err)
+	}
+	return path, servetest.Start(t, l, NewServer(m).Serve)
+}
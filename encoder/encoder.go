@@ -3,14 +3,46 @@
 // be found in the LICENSE file.
 
 // Package encoder provides encoding functionality for Base10 to Base62
-// conversion with/without paddings
+// conversion with/without paddings, and the order preserving Base32 and Base36
+// alternatives through the Encoding interface
 package encoder
 
+import (
+	"fmt"
+	"math/bits"
+)
+
 const (
 	maxBase62 = uint64(62)
 	mapping   = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	invalid   = 0xFF
+
+	errInvalidChar = "invalid char %q at position %d"
+	errOverflow    = "value %q overflows uint64"
 )
 
+var decoding = newDecoding(mapping)
+
+// InvalidCharError is an error type with the invalid char and its position
+type InvalidCharError struct {
+	Char     byte
+	Position int
+}
+
+func (e *InvalidCharError) Error() string {
+	return fmt.Sprintf(errInvalidChar, e.Char, e.Position)
+}
+
+// OverflowError is an error type with the encoded value which can't be
+// represented with uint64
+type OverflowError struct {
+	Value string
+}
+
+func (e *OverflowError) Error() string {
+	return fmt.Sprintf(errOverflow, e.Value)
+}
+
 // ToBase62WithPaddingZeros converts int types to Base62 encoded byte array
 // with padding zeros
 func ToBase62WithPaddingZeros(u uint64, length int) []byte {
@@ -36,6 +68,60 @@ func ToBase62WithPaddingZeros(u uint64, length int) []byte {
 	return a[i:]
 }
 
+// AppendBase62Padded appends the Base62 encoded value with padding zeros up to
+// the given length to dst and returns the extended buffer. It doesn't allocate
+// when dst has enough capacity.
+func AppendBase62Padded(dst []byte, u uint64, length int) []byte {
+	size := Base62ByteSize(u)
+	if size < length {
+		size = length
+	}
+	for i := 0; i < size; i++ {
+		dst = append(dst, mapping[0])
+	}
+
+	i := len(dst)
+	for u >= maxBase62 {
+		i--
+		// See ToBase62WithPaddingZeros for avoiding the modulo operation
+		q := u / maxBase62
+		dst[i] = mapping[u-q*maxBase62]
+		u = q
+	}
+	dst[i-1] = mapping[u]
+	return dst
+}
+
+// FromBase62 converts Base62 encoded byte array to uint64, the padding zeros
+// are allowed
+func FromBase62(b []byte) (uint64, error) {
+	var u uint64
+	for i, c := range b {
+		d := decoding[c]
+		if d == invalid {
+			return 0, &InvalidCharError{Char: c, Position: i}
+		}
+		hi, lo := bits.Mul64(u, maxBase62)
+		lo, carry := bits.Add64(lo, uint64(d), 0)
+		if hi != 0 || carry != 0 {
+			return 0, &OverflowError{Value: string(b)}
+		}
+		u = lo
+	}
+	return u, nil
+}
+
+// ValidateBase62 returns an *InvalidCharError for the first char of the byte
+// array which is not in the Base62 alphabet
+func ValidateBase62(b []byte) error {
+	for i, c := range b {
+		if decoding[c] == invalid {
+			return &InvalidCharError{Char: c, Position: i}
+		}
+	}
+	return nil
+}
+
 // Base62ByteSize returns the minimum byte size length requirement to allocate
 // the given unsigned integer's value
 func Base62ByteSize(u uint64) int {
@@ -47,3 +133,14 @@ func Base62ByteSize(u uint64) int {
 	}
 	return i + 1
 }
+
+func newDecoding(m string) [256]byte {
+	var d [256]byte
+	for i := range d {
+		d[i] = invalid
+	}
+	for i := 0; i < len(m); i++ {
+		d[m[i]] = byte(i)
+	}
+	return d
+}
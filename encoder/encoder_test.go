@@ -24,6 +24,52 @@ func TestToBase62WithPaddingZeros(t *testing.T) {
 	}
 }
 
+func TestAppendBase62Padded(t *testing.T) {
+	tests := []struct {
+		dst     string
+		val     uint64
+		padding int
+		want    string
+	}{
+		{"", 1, 11, "00000000001"},
+		{"x", 63, 2, "x11"},
+		{"xy", 124, 3, "xy020"},
+		{"", 125, 4, "0021"},
+		{"", 125, 0, "21"},
+		{"", 0, 0, "0"},
+		{"-", 1<<64 - 1, 11, "-LygHa16AHYF"},
+	}
+
+	msg := "AppendBase62Padded(%q, %d, %d) = %v, but returned %v"
+	for _, test := range tests {
+		got := AppendBase62Padded([]byte(test.dst), test.val, test.padding)
+		if string(got) != test.want {
+			t.Errorf(msg, test.dst, test.val, test.padding, test.want, string(got))
+		}
+	}
+
+	t.Run("does not allocate with enough capacity", func(t *testing.T) {
+		buf := make([]byte, 0, 16)
+		allocs := testing.AllocsPerRun(100, func() {
+			buf = AppendBase62Padded(buf[:0], 1<<64-1, 16)
+		})
+		if allocs != 0 {
+			t.Errorf("AppendBase62Padded() want 0 allocs, got: %f", allocs)
+		}
+	})
+}
+
+func TestValidateBase62(t *testing.T) {
+	if err := ValidateBase62([]byte(mapping)); err != nil {
+		t.Errorf("ValidateBase62(%s) returned error: %v", mapping, err)
+	}
+
+	want := "invalid char '_' at position 3"
+	if err := ValidateBase62([]byte("abc_")); err == nil || err.Error() != want {
+		t.Errorf("ValidateBase62(abc_) want error: %s, got: %v", want, err)
+	}
+}
+
 func TestBase62ByteSize(t *testing.T) {
 	tests := []struct {
 		val  uint64
@@ -45,3 +91,45 @@ func TestBase62ByteSize(t *testing.T) {
 		}
 	}
 }
+
+func TestFromBase62(t *testing.T) {
+	tests := []struct {
+		val  string
+		want uint64
+	}{
+		{"00000000001", 1},
+		{"11", 63},
+		{"020", 124},
+		{"0021", 125},
+		{"z", 61},
+		{"LygHa16AHYF", 1<<64 - 1},
+	}
+
+	msg := "FromBase62(%s) = %d, but returned %d"
+	for _, test := range tests {
+		got, err := FromBase62([]byte(test.val))
+		if err != nil {
+			t.Errorf("FromBase62(%s) returned error: %v", test.val, err)
+		}
+		if got != test.want {
+			t.Errorf(msg, test.val, test.want, got)
+		}
+	}
+
+	errorTests := []struct {
+		val  string
+		want string
+	}{
+		{"00-1", `invalid char '-' at position 2`},
+		{"LygHa16AHYG", `value "LygHa16AHYG" overflows uint64`},
+		{"zzzzzzzzzzzz", `value "zzzzzzzzzzzz" overflows uint64`},
+	}
+
+	msg = "FromBase62(%s) want error: %s, got: %v"
+	for _, test := range errorTests {
+		_, err := FromBase62([]byte(test.val))
+		if err == nil || err.Error() != test.want {
+			t.Errorf(msg, test.val, test.want, err)
+		}
+	}
+}
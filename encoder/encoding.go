@@ -0,0 +1,136 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package encoder
+
+import "math/bits"
+
+const (
+	crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	base36    = "0123456789abcdefghijklmnopqrstuvwxyz"
+)
+
+// Encoding is a fixed-length text encoding for uint64 values. The alphabets
+// are in ascending ASCII order, so the lexicographical order of the padded
+// values is same as their numeric order.
+type Encoding interface {
+	// Base returns the number of the chars in the alphabet
+	Base() uint64
+	// ByteSize returns the byte size of the encoded value without paddings
+	ByteSize(u uint64) int
+	// AppendPadded appends the encoded value with padding zeros up to the
+	// given length to dst and returns the extended buffer
+	AppendPadded(dst []byte, u uint64, length int) []byte
+	// Decode converts the encoded value into uint64
+	Decode(b []byte) (uint64, error)
+}
+
+var (
+	// Base62 is the case-sensitive default encoding with 0-9, A-Z and a-z
+	Base62 Encoding = base62Encoding{}
+
+	// Base32 is the Crockford Base32 encoding, it decodes the lower case chars
+	// and the I, L and O aliases
+	Base32 Encoding = newAlphabet(crockford, "IiLlOo", "111100")
+
+	// Base36 is the lower case Base36 encoding, it decodes the upper case
+	// chars
+	Base36 Encoding = newAlphabet(base36, "", "")
+)
+
+type base62Encoding struct{}
+
+func (base62Encoding) Base() uint64 {
+	return maxBase62
+}
+
+func (base62Encoding) ByteSize(u uint64) int {
+	return Base62ByteSize(u)
+}
+
+func (base62Encoding) AppendPadded(dst []byte, u uint64, length int) []byte {
+	return AppendBase62Padded(dst, u, length)
+}
+
+func (base62Encoding) Decode(b []byte) (uint64, error) {
+	return FromBase62(b)
+}
+
+// alphabet is an Encoding for the bases which decode the chars case
+// insensitively
+type alphabet struct {
+	base     uint64
+	mapping  string
+	decoding [256]byte
+}
+
+// newAlphabet returns an alphabet with the given mapping, the aliases are
+// decoded as the chars at the same positions of the targets
+func newAlphabet(mapping, aliases, targets string) *alphabet {
+	a := &alphabet{
+		base:     uint64(len(mapping)),
+		mapping:  mapping,
+		decoding: newDecoding(mapping),
+	}
+	for i := 0; i < len(mapping); i++ {
+		c := mapping[i]
+		switch {
+		case c >= 'A' && c <= 'Z':
+			a.decoding[c+'a'-'A'] = a.decoding[c]
+		case c >= 'a' && c <= 'z':
+			a.decoding[c-'a'+'A'] = a.decoding[c]
+		}
+	}
+	for i := 0; i < len(aliases); i++ {
+		a.decoding[aliases[i]] = a.decoding[targets[i]]
+	}
+	return a
+}
+
+func (a *alphabet) Base() uint64 {
+	return a.base
+}
+
+func (a *alphabet) ByteSize(u uint64) int {
+	size := 1
+	for u >= a.base {
+		u /= a.base
+		size++
+	}
+	return size
+}
+
+func (a *alphabet) AppendPadded(dst []byte, u uint64, length int) []byte {
+	size := a.ByteSize(u)
+	if size < length {
+		size = length
+	}
+	for i := 0; i < size; i++ {
+		dst = append(dst, a.mapping[0])
+	}
+
+	for i := len(dst) - 1; u > 0; i-- {
+		q := u / a.base
+		dst[i] = a.mapping[u-q*a.base]
+		u = q
+	}
+	return dst
+}
+
+func (a *alphabet) Decode(b []byte) (uint64, error) {
+	var u uint64
+	for i, c := range b {
+		d := a.decoding[c]
+		if d == invalid {
+			return 0, &InvalidCharError{Char: c, Position: i}
+		}
+		hi, lo := bits.Mul64(u, a.base)
+		lo, carry := bits.Add64(lo, uint64(d), 0)
+		if hi != 0 || carry != 0 {
+			return 0, &OverflowError{Value: string(b)}
+		}
+		u = lo
+	}
+	return u, nil
+}
@@ -0,0 +1,117 @@
+package encoder
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestEncodingAppendPadded(t *testing.T) {
+	tests := []struct {
+		e       Encoding
+		val     uint64
+		padding int
+		want    string
+	}{
+		{Base62, 125, 4, "0021"},
+		{Base32, 0, 0, "0"},
+		{Base32, 31, 2, "0Z"},
+		{Base32, 32, 2, "10"},
+		{Base32, 1234567, 8, "00015NM7"},
+		{Base32, 1<<64 - 1, 13, "FZZZZZZZZZZZZ"},
+		{Base36, 0, 3, "000"},
+		{Base36, 35, 0, "z"},
+		{Base36, 1234567, 5, "0qglj"},
+		{Base36, 1<<64 - 1, 13, "3w5e11264sgsf"},
+	}
+
+	msg := "AppendPadded(%d, %d) with base %d = %v, but returned %v"
+	for _, test := range tests {
+		got := test.e.AppendPadded([]byte("-"), test.val, test.padding)
+		if string(got) != "-"+test.want {
+			t.Errorf(msg, test.val, test.padding, test.e.Base(), "-"+test.want, string(got))
+		}
+		if size := test.e.ByteSize(test.val); size > len(test.want) {
+			t.Errorf("ByteSize(%d) with base %d = %d", test.val, test.e.Base(), size)
+		}
+	}
+}
+
+func TestEncodingByteSize(t *testing.T) {
+	tests := []struct {
+		e    Encoding
+		val  uint64
+		want int
+	}{
+		{Base62, 1<<64 - 1, 11},
+		{Base32, 0, 1},
+		{Base32, 31, 1},
+		{Base32, 32, 2},
+		{Base32, 1<<64 - 1, 13},
+		{Base36, 35, 1},
+		{Base36, 36, 2},
+		{Base36, 1<<64 - 1, 13},
+	}
+
+	for _, test := range tests {
+		if got := test.e.ByteSize(test.val); got != test.want {
+			t.Errorf("ByteSize(%d) with base %d = %d, but returned %d", test.val, test.e.Base(), test.want, got)
+		}
+	}
+}
+
+func TestEncodingDecode(t *testing.T) {
+	tests := []struct {
+		e       Encoding
+		val     string
+		want    uint64
+		wantErr string
+	}{
+		{Base62, "0021", 125, ""},
+		{Base32, "00015NM7", 1234567, ""},
+		{Base32, "00015nm7", 1234567, ""},
+		{Base32, "OIL", 33, ""},
+		{Base32, "oil", 33, ""},
+		{Base32, "FZZZZZZZZZZZZ", 1<<64 - 1, ""},
+		{Base32, "G0000000000000", 0, `value "G0000000000000" overflows uint64`},
+		{Base32, "00U", 0, "invalid char 'U' at position 2"},
+		{Base36, "0qglj", 1234567, ""},
+		{Base36, "0QGLJ", 1234567, ""},
+		{Base36, "3w5e11264sgsg", 0, `value "3w5e11264sgsg" overflows uint64`},
+		{Base36, "00-", 0, "invalid char '-' at position 2"},
+	}
+
+	for _, test := range tests {
+		got, err := test.e.Decode([]byte(test.val))
+		if test.wantErr != "" {
+			if err == nil || err.Error() != test.wantErr {
+				t.Errorf("Decode(%s) want err: %s, got: %v", test.val, test.wantErr, err)
+			}
+			continue
+		}
+		if err != nil || got != test.want {
+			t.Errorf("Decode(%s) with base %d = %d, but returned %d, err: %v", test.val, test.e.Base(), test.want, got, err)
+		}
+	}
+}
+
+func TestEncodingOrder(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, e := range []Encoding{Base62, Base32, Base36} {
+		for i := 0; i < 1000; i++ {
+			a, b := r.Uint64()>>uint(r.Intn(64)), r.Uint64()>>uint(r.Intn(64))
+			ea := string(e.AppendPadded(nil, a, 13))
+			eb := string(e.AppendPadded(nil, b, 13))
+
+			want := 0
+			if a < b {
+				want = -1
+			} else if a > b {
+				want = 1
+			}
+			if got := strings.Compare(ea, eb); got != want {
+				t.Fatalf("Compare(%s, %s) with base %d = %d, but returned %d", ea, eb, e.Base(), want, got)
+			}
+		}
+	}
+}
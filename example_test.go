@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/mustafaturan/monoton/v3"
+	"github.com/mustafaturan/monoton/v3/mtimer"
 	"github.com/mustafaturan/monoton/v3/sequencer"
 )
 
@@ -26,6 +27,24 @@ func ExampleNew() {
 	// 000J
 }
 
+func ExampleNew_fakeClock() {
+	c := mtimer.NewFakeClock(time.Unix(1, 0)) // frozen at 1000 ms
+	s := sequencer.NewMillisecond(sequencer.WithClock(c))
+
+	m, err := monoton.New(s, 19, 0)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(m.Next())
+	fmt.Println(m.Next())
+	c.Advance(time.Millisecond)
+	fmt.Println(m.Next())
+	// Output:
+	// 000000G80000000J
+	// 000000G80001000J
+	// 000000G90000000J
+}
+
 func ExampleMonoton_Next() {
 	s := sequencer.NewSecond()     // sequencer.Second
 	n := uint64(19)                // Base62 => J
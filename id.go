@@ -0,0 +1,193 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package monoton
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3/encoder"
+)
+
+const errScanType = "unsupported scan type %T for %s"
+
+// ID is a Base62 encoded 16 bytes identifier. The zero ID represents a
+// missing identifier, it is encoded as an empty text, JSON null and SQL NULL.
+type ID [totalByteSize]byte
+
+// ScanTypeError is an error type with the unsupported scan value and the name
+// of the target type
+type ScanTypeError struct {
+	Value  interface{}
+	Target string
+}
+
+func (e *ScanTypeError) Error() string {
+	return fmt.Sprintf(errScanType, e.Value, e.Target)
+}
+
+// NextID generates next incremental unique identifier as ID
+func (m Monoton) NextID() ID {
+	return ID(m.NextBytes())
+}
+
+// TryNextID generates next incremental unique identifier as ID like NextID,
+// but returns an error instead of a corrupt identifier
+func (m Monoton) TryNextID() (ID, error) {
+	val, err := m.TryNextBytes()
+	return ID(val), err
+}
+
+// MinID returns the smallest identifier which can be generated at the given
+// time, with the counter and the node set to their min values
+func (m Monoton) MinID(t time.Time) (ID, error) {
+	v, err := m.fromTime(t)
+	if err != nil {
+		return ID{}, err
+	}
+	return m.boundary(v, 0, 0), nil
+}
+
+// MaxID returns the largest identifier which can be generated at the given
+// time, with the counter and the node set to their max values
+func (m Monoton) MaxID(t time.Time) (ID, error) {
+	v, err := m.fromTime(t)
+	if err != nil {
+		return ID{}, err
+	}
+	return m.boundary(v, m.sequencer.Max(), m.sequencer.MaxNode()), nil
+}
+
+// boundary encodes the time sequence value and the counter with the layout of
+// the generated identifiers and replaces the node with the given one
+func (m Monoton) boundary(t, seq, node uint64) ID {
+	id := m.encode(t, seq)
+	nodeOffset := m.timeSeqByteSize + m.seqByteSize
+	m.encoding.AppendPadded(
+		id[nodeOffset:nodeOffset],
+		node,
+		m.nodeByteSize(),
+	)
+	return ID(id)
+}
+
+// Compare returns an integer comparing two identifiers in their generation
+// order. The result will be 0 if a == b, -1 if a < b, and +1 if a > b.
+func Compare(a, b ID) int {
+	return bytes.Compare(a[:], b[:])
+}
+
+// ParseID validates the length and the Base62 alphabet of the given string
+// and converts it into an ID
+func ParseID(s string) (ID, error) {
+	var id ID
+	if len(s) != totalByteSize {
+		return id, &InvalidByteSizeError{
+			ByteSize:      len(s),
+			ByteSizeTotal: totalByteSize,
+		}
+	}
+	copy(id[:], s)
+	if err := encoder.ValidateBase62(id[:]); err != nil {
+		return ID{}, err
+	}
+	return id, nil
+}
+
+// IsZero reports whether the id is the zero ID
+func (id ID) IsZero() bool {
+	return id == ID{}
+}
+
+// Before reports whether the id is generated before the other id
+func (id ID) Before(other ID) bool {
+	return Compare(id, other) < 0
+}
+
+// After reports whether the id is generated after the other id
+func (id ID) After(other ID) bool {
+	return Compare(id, other) > 0
+}
+
+// String returns the Base62 representation of the id
+func (id ID) String() string {
+	if id.IsZero() {
+		return ""
+	}
+	return string(id[:])
+}
+
+// MarshalText implements the encoding.TextMarshaler interface
+func (id ID) MarshalText() ([]byte, error) {
+	if id.IsZero() {
+		return []byte{}, nil
+	}
+	return id[:], nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface
+func (id *ID) UnmarshalText(b []byte) error {
+	if len(b) == 0 {
+		*id = ID{}
+		return nil
+	}
+	parsed, err := ParseID(string(b))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (id ID) MarshalJSON() ([]byte, error) {
+	if id.IsZero() {
+		return []byte("null"), nil
+	}
+	b := make([]byte, 0, totalByteSize+2)
+	b = append(b, '"')
+	b = append(b, id[:]...)
+	return append(b, '"'), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (id *ID) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*id = ID{}
+		return nil
+	}
+	if len(b) < 2 || b[0] != '"' || b[len(b)-1] != '"' {
+		return &InvalidByteSizeError{
+			ByteSize:      len(b),
+			ByteSizeTotal: totalByteSize + 2,
+		}
+	}
+	return id.UnmarshalText(b[1 : len(b)-1])
+}
+
+// Scan implements the sql.Scanner interface
+func (id *ID) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*id = ID{}
+		return nil
+	case string:
+		return id.UnmarshalText([]byte(v))
+	case []byte:
+		return id.UnmarshalText(v)
+	default:
+		return &ScanTypeError{Value: src, Target: "ID"}
+	}
+}
+
+// Value implements the driver.Valuer interface
+func (id ID) Value() (driver.Value, error) {
+	if id.IsZero() {
+		return nil, nil
+	}
+	return string(id[:]), nil
+}
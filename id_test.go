@@ -0,0 +1,231 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package monoton
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3/sequencer"
+)
+
+func TestNextID(t *testing.T) {
+	m, _ := New(&validSequencer{}, 3843, 0)
+	id1, id2 := m.NextID(), m.NextID()
+
+	if strings.Compare(id1.String(), id2.String()) >= 0 {
+		t.Errorf("NextID(): %s >= NextID(): %s", id1, id2)
+	}
+
+	m, _ = New(&fixedSequencer{}, 3843, 0)
+	if id, err := m.TryNextID(); err != nil || id.IsZero() {
+		t.Errorf("TryNextID() want an id, got: %s, err: %v", id, err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	m, _ := New(sequencer.NewMillisecond(), 3, 0)
+	id1, id2 := m.NextID(), m.NextID()
+
+	if got := Compare(id1, id2); got != -1 {
+		t.Errorf("Compare(%s, %s) want: -1, got: %d", id1, id2, got)
+	}
+	if got := Compare(id2, id1); got != 1 {
+		t.Errorf("Compare(%s, %s) want: 1, got: %d", id2, id1, got)
+	}
+	if got := Compare(id1, id1); got != 0 {
+		t.Errorf("Compare(%s, %s) want: 0, got: %d", id1, id1, got)
+	}
+	if !id1.Before(id2) || id2.Before(id1) {
+		t.Errorf("%s.Before(%s) want true", id1, id2)
+	}
+	if !id2.After(id1) || id1.After(id2) {
+		t.Errorf("%s.After(%s) want true", id2, id1)
+	}
+}
+
+func TestMinMaxID(t *testing.T) {
+	from := time.Now()
+	m, _ := New(sequencer.NewMillisecond(), 3, 0)
+	id := m.NextID()
+	to := time.Now()
+
+	lo, err := m.MinID(from)
+	if err != nil {
+		t.Fatalf("MinID(%s) returned error: %v", from, err)
+	}
+	hi, err := m.MaxID(to)
+	if err != nil {
+		t.Fatalf("MaxID(%s) returned error: %v", to, err)
+	}
+	if id.Before(lo) || id.After(hi) {
+		t.Errorf("%s is not between MinID: %s and MaxID: %s", id, lo, hi)
+	}
+
+	c, _ := m.ParseBytes(lo)
+	if c.Counter != 0 || c.Node != 0 {
+		t.Errorf("MinID(%s) want min counter and node, got: %+v", from, c)
+	}
+	c, _ = m.ParseBytes(hi)
+	if c.Counter != m.sequencer.Max() || c.Node != m.sequencer.MaxNode() {
+		t.Errorf("MaxID(%s) want max counter and node, got: %+v", to, c)
+	}
+
+	next, _ := m.MinID(to.Add(time.Millisecond))
+	if !hi.Before(next) {
+		t.Errorf("MaxID: %s want before the next MinID: %s", hi, next)
+	}
+}
+
+func TestMinIDErrors(t *testing.T) {
+	initialTime := uint64(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano()) /
+		uint64(time.Millisecond)
+	m, _ := New(sequencer.NewMillisecond(), 3, initialTime)
+
+	tests := []struct {
+		t       time.Time
+		wantErr string
+	}{
+		{
+			time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC),
+			"time 1969-12-31T00:00:00Z is before the unix epoch",
+		},
+		{
+			time.Date(2019, 12, 31, 23, 59, 59, 999000000, time.UTC),
+			"time can't be less than the initial time 1577836800000 (given 1577836799999)",
+		},
+		{
+			time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC),
+			"time can't be greater than 218340105584895 (given 251792928000000)",
+		},
+		{
+			time.Unix(1<<62, 0),
+			"time can't be greater than 218340105584895 (given 18446744073709551615)",
+		},
+	}
+
+	for _, test := range tests {
+		if _, err := m.MinID(test.t); err == nil || err.Error() != test.wantErr {
+			t.Errorf("MinID(%s) want err: %s, got: %v", test.t, test.wantErr, err)
+		}
+		if _, err := m.MaxID(test.t); err == nil || err.Error() != test.wantErr {
+			t.Errorf("MaxID(%s) want err: %s, got: %v", test.t, test.wantErr, err)
+		}
+	}
+}
+
+func TestParseID(t *testing.T) {
+	tests := []struct {
+		s       string
+		wantErr string
+	}{
+		{"000000G80001000J", ""},
+		{"000000G80001000", "byte size must be 16 (given 15)"},
+		{"000000G8000-000J", "invalid char '-' at position 11"},
+	}
+
+	for _, test := range tests {
+		id, err := ParseID(test.s)
+		if test.wantErr == "" {
+			if err != nil || id.String() != test.s {
+				t.Errorf("ParseID(%s) got: %s, err: %v", test.s, id, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != test.wantErr {
+			t.Errorf("ParseID(%s) want err: %s, got: %v", test.s, test.wantErr, err)
+		}
+		if !id.IsZero() {
+			t.Errorf("ParseID(%s) want zero id, got: %s", test.s, id)
+		}
+	}
+}
+
+func TestIDText(t *testing.T) {
+	id, _ := ParseID("000000G80001000J")
+
+	b, err := id.MarshalText()
+	if err != nil || string(b) != "000000G80001000J" {
+		t.Errorf("MarshalText() got: %s, err: %v", b, err)
+	}
+
+	var got ID
+	if err := got.UnmarshalText(b); err != nil || got != id {
+		t.Errorf("UnmarshalText(%s) got: %s, err: %v", b, got, err)
+	}
+
+	if err := got.UnmarshalText([]byte{}); err != nil || !got.IsZero() {
+		t.Errorf("UnmarshalText() want zero id, got: %s, err: %v", got, err)
+	}
+
+	if err := got.UnmarshalText([]byte("000000G8000_000J")); err == nil {
+		t.Error("UnmarshalText(000000G8000_000J) want err, got nil")
+	}
+}
+
+func TestIDJSON(t *testing.T) {
+	type record struct {
+		ID     ID `json:"id"`
+		Parent ID `json:"parent"`
+	}
+	id, _ := ParseID("000000G80001000J")
+
+	b, err := json.Marshal(record{ID: id})
+	want := `{"id":"000000G80001000J","parent":null}`
+	if err != nil || string(b) != want {
+		t.Errorf("json.Marshal() want: %s, got: %s, err: %v", want, b, err)
+	}
+
+	var r record
+	if err := json.Unmarshal(b, &r); err != nil || r.ID != id {
+		t.Errorf("json.Unmarshal(%s) got: %+v, err: %v", b, r, err)
+	}
+	if !r.Parent.IsZero() {
+		t.Errorf("json.Unmarshal(%s) want zero parent, got: %s", b, r.Parent)
+	}
+
+	invalids := []string{
+		`{"id":"000000G8000_000J"}`,
+		`{"id":"000000G80001"}`,
+		`{"id":12}`,
+	}
+	for _, invalid := range invalids {
+		if err := json.Unmarshal([]byte(invalid), &r); err == nil {
+			t.Errorf("json.Unmarshal(%s) want err, got nil", invalid)
+		}
+	}
+}
+
+func TestIDSQL(t *testing.T) {
+	id, _ := ParseID("000000G80001000J")
+
+	v, err := id.Value()
+	if err != nil || v != "000000G80001000J" {
+		t.Errorf("Value() got: %v, err: %v", v, err)
+	}
+	if v, err := (ID{}).Value(); err != nil || v != nil {
+		t.Errorf("Value() of zero id want nil, got: %v, err: %v", v, err)
+	}
+
+	srcs := []interface{}{"000000G80001000J", []byte("000000G80001000J")}
+	for _, src := range srcs {
+		var got ID
+		if err := got.Scan(src); err != nil || got != id {
+			t.Errorf("Scan(%v) got: %s, err: %v", src, got, err)
+		}
+	}
+
+	got := id
+	if err := got.Scan(nil); err != nil || !got.IsZero() {
+		t.Errorf("Scan(nil) want zero id, got: %s, err: %v", got, err)
+	}
+
+	want := "unsupported scan type int64 for ID"
+	if err := got.Scan(int64(1)); err == nil || err.Error() != want {
+		t.Errorf("Scan(1) want err: %s, got: %v", want, err)
+	}
+}
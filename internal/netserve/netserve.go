@@ -0,0 +1,147 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+// Package netserve provides the connection loop of the stream servers which
+// serve each connection on its own goroutine and shut down gracefully
+package netserve
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// minAcceptDelay and maxAcceptDelay bound the backoff between the retries
+	// of the temporary accept errors like running out of file descriptors
+	minAcceptDelay = 5 * time.Millisecond
+	maxAcceptDelay = time.Second
+)
+
+// Server accepts the connections of a listener and tracks them to interrupt
+// the idle ones on shutdown
+type Server struct {
+	// Handle serves the connection until it returns, the connection is closed
+	// afterwards
+	Handle func(net.Conn)
+	// MaxConns is the max number of the concurrent connections, zero or a
+	// negative value means no limit
+	MaxConns int
+	// Reject is called with the connections over MaxConns before they are
+	// closed, it can be nil
+	Reject func(net.Conn)
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+	wg    sync.WaitGroup
+}
+
+// Serve accepts the connections on the listener until the context is done,
+// then closes the listener, interrupts the reads of the connections and waits
+// the handlers to return, so the requests which are already read are replied.
+//
+// The temporary accept errors are retried with a backoff, any other error
+// stops the server and is returned.
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	s.mu.Lock()
+	s.conns = make(map[net.Conn]struct{})
+	s.mu.Unlock()
+
+	stopped := make(chan struct{})
+	defer close(stopped)
+	go func() {
+		select {
+		case <-ctx.Done():
+			l.Close()
+			s.interrupt()
+		case <-stopped:
+		}
+	}()
+
+	var sem chan struct{}
+	if s.MaxConns > 0 {
+		sem = make(chan struct{}, s.MaxConns)
+	}
+	var delay time.Duration
+	for {
+		conn, err := l.Accept()
+		if ne, ok := err.(net.Error); ok && ne.Temporary() && ctx.Err() == nil {
+			if delay == 0 {
+				delay = minAcceptDelay
+			} else if delay *= 2; delay > maxAcceptDelay {
+				delay = maxAcceptDelay
+			}
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+			}
+			continue
+		}
+		if err != nil {
+			s.interrupt()
+			s.wg.Wait()
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		delay = 0
+
+		if sem != nil {
+			select {
+			case sem <- struct{}{}:
+			default:
+				if s.Reject != nil {
+					s.Reject(conn)
+				}
+				conn.Close()
+				continue
+			}
+		}
+
+		s.track(conn)
+		s.wg.Add(1)
+		go func() {
+			defer func() {
+				s.untrack(conn)
+				conn.Close()
+				if sem != nil {
+					<-sem
+				}
+				s.wg.Done()
+			}()
+			s.Handle(conn)
+		}()
+	}
+}
+
+// interrupt unblocks the connections which are waiting for the next request
+func (s *Server) interrupt() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		conn.SetReadDeadline(time.Now())
+	}
+	// the connections which are accepted afterwards are interrupted on track
+	s.conns = nil
+}
+
+func (s *Server) track(conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conns == nil {
+		conn.SetReadDeadline(time.Now())
+		return
+	}
+	s.conns[conn] = struct{}{}
+}
+
+func (s *Server) untrack(conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, conn)
+}
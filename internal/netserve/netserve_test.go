@@ -0,0 +1,140 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package netserve
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3/internal/servetest"
+)
+
+// echo replies the lines until the connection is interrupted
+func echo(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		conn.Write([]byte(line))
+	}
+}
+
+func start(t *testing.T, s *Server) (string, func()) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() returned error: %v", err)
+	}
+	return l.Addr().String(), servetest.Start(t, l, s.Serve)
+}
+
+// temporaryError is a net.Error like the one of running out of file
+// descriptors
+type temporaryError struct{}
+
+func (temporaryError) Error() string   { return "too many open files" }
+func (temporaryError) Timeout() bool   { return false }
+func (temporaryError) Temporary() bool { return true }
+
+// failingListener fails the first accepts with temporary errors
+type failingListener struct {
+	net.Listener
+	failures int
+}
+
+func (l *failingListener) Accept() (net.Conn, error) {
+	if l.failures > 0 {
+		l.failures--
+		return nil, temporaryError{}
+	}
+	return l.Listener.Accept()
+}
+
+func dial(t *testing.T, addr string) net.Conn {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("net.Dial() returned error: %v", err)
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+func TestServe(t *testing.T) {
+	t.Run("serves the connections", func(t *testing.T) {
+		addr, stop := start(t, &Server{Handle: echo})
+		defer stop()
+
+		conn := dial(t, addr)
+		defer conn.Close()
+		conn.Write([]byte("ping\n"))
+		got, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil || got != "ping\n" {
+			t.Errorf("echo want: ping, got: %q, %v", got, err)
+		}
+	})
+
+	t.Run("retries the temporary accept errors", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("net.Listen() returned error: %v", err)
+		}
+		s := &Server{Handle: echo}
+		stop := servetest.Start(t, &failingListener{Listener: l, failures: 3},
+			s.Serve)
+		defer stop()
+
+		conn := dial(t, l.Addr().String())
+		defer conn.Close()
+		conn.Write([]byte("ping\n"))
+		got, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil || got != "ping\n" {
+			t.Errorf("echo want: ping, got: %q, %v", got, err)
+		}
+	})
+
+	t.Run("interrupts the idle connections on shutdown", func(t *testing.T) {
+		addr, stop := start(t, &Server{Handle: echo})
+
+		conn := dial(t, addr)
+		defer conn.Close()
+		conn.Write([]byte("ping\n"))
+		r := bufio.NewReader(conn)
+		r.ReadString('\n')
+
+		stop()
+		if _, err := r.ReadString('\n'); err != io.EOF {
+			t.Errorf("read after shutdown want: EOF, got: %v", err)
+		}
+	})
+
+	t.Run("rejects the connections over the max", func(t *testing.T) {
+		addr, stop := start(t, &Server{
+			Handle:   echo,
+			MaxConns: 1,
+			Reject: func(conn net.Conn) {
+				conn.Write([]byte("busy\n"))
+			},
+		})
+		defer stop()
+
+		first := dial(t, addr)
+		defer first.Close()
+		first.Write([]byte("ping\n"))
+		bufio.NewReader(first).ReadString('\n')
+
+		second := dial(t, addr)
+		defer second.Close()
+		got, err := bufio.NewReader(second).ReadString('\n')
+		if err != nil || got != "busy\n" {
+			t.Errorf("second connection want: busy, got: %q, %v", got, err)
+		}
+	})
+}
@@ -0,0 +1,41 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+// Package servetest provides the test helpers of the servers
+package servetest
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+// Start serves the listener with the serve function in the background and
+// returns a function which stops the server and reports its error, the stop
+// function can be called more than once
+func Start(
+	t *testing.T,
+	l net.Listener,
+	serve func(context.Context, net.Listener) error,
+) func() {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		errs <- serve(ctx, l)
+	}()
+
+	stopped := false
+	return func() {
+		if stopped {
+			return
+		}
+		stopped = true
+		cancel()
+		if err := <-errs; err != nil {
+			t.Errorf("Serve() returned error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package monoton
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3/sequencer"
+)
+
+const (
+	// renewDivider splits the ttl to renew the lease before it expires
+	renewDivider = 3
+
+	// minLeaseTTL is the shortest ttl, it renews the lease every millisecond
+	minLeaseTTL = renewDivider * time.Millisecond
+
+	errLeaseLost = "lease of node %d is lost"
+	errLeaseTTL  = "lease ttl must be at least %s (given %s)"
+)
+
+// NodeLeaser leases node identifiers for a limited time, so the generators of
+// an autoscaled fleet don't need a static node configuration
+type NodeLeaser interface {
+	// Acquire leases a free node identifier up to the max node for the ttl
+	Acquire(maxNode uint64, ttl time.Duration) (uint64, error)
+	// Renew extends the lease of the node for the ttl
+	Renew(node uint64, ttl time.Duration) error
+	// Release gives the lease of the node back
+	Release(node uint64) error
+}
+
+// LeaseLostError is an error type with the node information
+type LeaseLostError struct {
+	Node uint64
+}
+
+func (e *LeaseLostError) Error() string {
+	return fmt.Sprintf(errLeaseLost, e.Node)
+}
+
+// LeaseTTLError is an error type with the given and the min lease ttl
+type LeaseTTLError struct {
+	TTL    time.Duration
+	MinTTL time.Duration
+}
+
+func (e *LeaseTTLError) Error() string {
+	return fmt.Sprintf(errLeaseTTL, e.MinTTL, e.TTL)
+}
+
+// Leased is a sequential id generator which generates identifiers only while
+// it holds the lease of its node
+type Leased struct {
+	// deadline is the monotonic nanoseconds since start until the lease is
+	// valid, it is the first field to keep the 64-bit atomic operations
+	// aligned
+	deadline int64
+	start    time.Time
+	monoton  Monoton
+	leaser   NodeLeaser
+	node     uint64
+	ttl      time.Duration
+	done     chan struct{}
+	wg       sync.WaitGroup
+	once     sync.Once
+}
+
+// NewLeased acquires a node lease from the leaser and inits a new monoton ID
+// generator with the leased node. The lease is renewed in the background until
+// Close is called, three times per ttl. The ttl must be at least 3ms,
+// otherwise a *LeaseTTLError is returned.
+func NewLeased(
+	s sequencer.Sequencer,
+	l NodeLeaser,
+	ttl time.Duration,
+	initialTime uint64,
+	opts ...Option,
+) (*Leased, error) {
+	if ttl < minLeaseTTL {
+		return nil, &LeaseTTLError{TTL: ttl, MinTTL: minLeaseTTL}
+	}
+
+	start := time.Now()
+	node, err := l.Acquire(s.MaxNode(), ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := New(s, node, initialTime, opts...)
+	if err != nil {
+		_ = l.Release(node)
+		return nil, err
+	}
+
+	leased := &Leased{
+		deadline: int64(ttl),
+		start:    start,
+		monoton:  m,
+		leaser:   l,
+		node:     node,
+		ttl:      ttl,
+		done:     make(chan struct{}),
+	}
+	leased.wg.Add(1)
+	go leased.renew()
+	return leased, nil
+}
+
+// Node returns the leased node identifier
+func (l *Leased) Node() uint64 {
+	return l.node
+}
+
+// TryNext generates next incremental unique identifier as Base62 or returns a
+// *LeaseLostError when the lease is expired
+func (l *Leased) TryNext() (string, error) {
+	val, err := l.TryNextBytes()
+	if err != nil {
+		return "", err
+	}
+	return string(val[:]), nil
+}
+
+// TryNextBytes generates next incremental unique identifier as Base62 16
+// bytes array or returns a *LeaseLostError when the lease is expired
+func (l *Leased) TryNextBytes() ([16]byte, error) {
+	if !l.valid() {
+		return [totalByteSize]byte{}, &LeaseLostError{Node: l.node}
+	}
+	return l.monoton.TryNextBytes()
+}
+
+// Close stops renewing the lease and releases the node
+func (l *Leased) Close() error {
+	var err error
+	l.once.Do(func() {
+		close(l.done)
+		l.wg.Wait()
+		atomic.StoreInt64(&l.deadline, 0)
+		err = l.leaser.Release(l.node)
+	})
+	return err
+}
+
+func (l *Leased) renew() {
+	defer l.wg.Done()
+
+	ticker := time.NewTicker(l.ttl / renewDivider)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-ticker.C:
+			// The deadline is calculated from the time before the renewal to
+			// never outlive the lease on the leaser side
+			renewedAt := time.Since(l.start)
+			if err := l.leaser.Renew(l.node, l.ttl); err != nil {
+				continue
+			}
+			atomic.StoreInt64(&l.deadline, int64(renewedAt+l.ttl))
+		}
+	}
+}
+
+func (l *Leased) valid() bool {
+	return int64(time.Since(l.start)) < atomic.LoadInt64(&l.deadline)
+}
@@ -0,0 +1,145 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package monoton
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewLeased(t *testing.T) {
+	t.Run("generates with the leased node", func(t *testing.T) {
+		l := &fakeLeaser{node: 7}
+		m, err := NewLeased(&validSequencer{}, l, time.Minute, 0)
+		if err != nil {
+			t.Fatalf("NewLeased() returned error: %v", err)
+		}
+		defer m.Close()
+
+		id, err := m.TryNext()
+		if err != nil || id[14:] != "07" || m.Node() != 7 {
+			t.Errorf("TryNext() want node: 07, got: %s, %v", id, err)
+		}
+		if l.maxNode != 3843 {
+			t.Errorf("Acquire() want max node: 3843, got: %d", l.maxNode)
+		}
+	})
+
+	t.Run("returns the acquire errors", func(t *testing.T) {
+		want := errors.New("no free node")
+		l := &fakeLeaser{acquireErr: want}
+		if _, err := NewLeased(&validSequencer{}, l, time.Minute, 0); err != want {
+			t.Errorf("NewLeased() want error: %v, got: %v", want, err)
+		}
+	})
+
+	t.Run("errors on short ttls before acquiring", func(t *testing.T) {
+		l := &fakeLeaser{node: 7}
+		_, err := NewLeased(&validSequencer{}, l, minLeaseTTL-1, 0)
+		var ttlErr *LeaseTTLError
+		if !errors.As(err, &ttlErr) ||
+			ttlErr.TTL != minLeaseTTL-1 || ttlErr.MinTTL != minLeaseTTL {
+			t.Errorf("NewLeased() want *LeaseTTLError, got: %v", err)
+		}
+		if l.maxNode != 0 {
+			t.Errorf("NewLeased() shouldn't acquire a node for short ttls")
+		}
+	})
+
+	t.Run("accepts the min ttl", func(t *testing.T) {
+		l := &fakeLeaser{node: 7}
+		m, err := NewLeased(&validSequencer{}, l, minLeaseTTL, 0)
+		if err != nil {
+			t.Fatalf("NewLeased() returned error: %v", err)
+		}
+		m.Close()
+	})
+
+	t.Run("releases the node on invalid configuration", func(t *testing.T) {
+		l := &fakeLeaser{node: 3844}
+		if _, err := NewLeased(&validSequencer{}, l, time.Minute, 0); err == nil {
+			t.Errorf("NewLeased() should return error for invalid nodes")
+		}
+		if l.released != 1 {
+			t.Errorf("NewLeased() should release the node, got: %d", l.released)
+		}
+	})
+}
+
+func TestLeased(t *testing.T) {
+	t.Run("renews the lease", func(t *testing.T) {
+		l := &fakeLeaser{node: 1}
+		m, _ := NewLeased(&validSequencer{}, l, 30*time.Millisecond, 0)
+		defer m.Close()
+
+		time.Sleep(100 * time.Millisecond)
+		if _, err := m.TryNextBytes(); err != nil || l.renewals() == 0 {
+			t.Errorf("TryNextBytes() want renewed lease, got: %d, %v", l.renewals(), err)
+		}
+	})
+
+	t.Run("refuses after the lease is lost", func(t *testing.T) {
+		l := &fakeLeaser{node: 1, renewErr: errors.New("lost")}
+		m, _ := NewLeased(&validSequencer{}, l, 30*time.Millisecond, 0)
+		defer m.Close()
+
+		time.Sleep(50 * time.Millisecond)
+		want := "lease of node 1 is lost"
+		if _, err := m.TryNext(); err == nil || err.Error() != want {
+			t.Errorf("TryNext() want error: %s, got: %v", want, err)
+		}
+	})
+
+	t.Run("releases the node on close", func(t *testing.T) {
+		l := &fakeLeaser{node: 1}
+		m, _ := NewLeased(&validSequencer{}, l, time.Minute, 0)
+		if err := m.Close(); err != nil || l.released != 1 {
+			t.Errorf("Close() want released node, got: %d, %v", l.released, err)
+		}
+		if err := m.Close(); err != nil || l.released != 1 {
+			t.Errorf("Close() should release the node once, got: %d", l.released)
+		}
+		if _, err := m.TryNext(); err == nil {
+			t.Errorf("TryNext() should return error after Close()")
+		}
+	})
+}
+
+type fakeLeaser struct {
+	mu         sync.Mutex
+	node       uint64
+	maxNode    uint64
+	renewed    int
+	released   int
+	acquireErr error
+	renewErr   error
+}
+
+func (f *fakeLeaser) Acquire(maxNode uint64, ttl time.Duration) (uint64, error) {
+	f.maxNode = maxNode
+	return f.node, f.acquireErr
+}
+
+func (f *fakeLeaser) Renew(node uint64, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.renewed++
+	return f.renewErr
+}
+
+func (f *fakeLeaser) Release(node uint64) error {
+	f.released++
+	return nil
+}
+
+func (f *fakeLeaser) renewals() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.renewed
+}
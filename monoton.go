@@ -16,7 +16,9 @@ clock.
 # Initial Time
 
 Initial time value opens space for the time value by subtracting the given
-value from the time sequence.
+value from the time sequence. The initial time can't be in the future of the
+sequencer time, and the remaining lifetime until the time value overflows can
+be checked with the Expiry method.
 
 # Readable
 
@@ -27,25 +29,32 @@ read, predict the order by a human eye.
 The total byte size is fixed to 16 bytes for all sequencers. And at least one
 byte is reserved to nodes.
 
+The WithEncoding option switches to the case-insensitive Crockford Base32 or
+lower case Base36 encodings of the monoton/encoder package for the sequencers
+which are configured for the same encoding with sequencer.NewCustom. The
+pre-configured sequencers are only laid out for Base62.
+
 # Multi Node Support
 
 The monoton package can be used on single/multiple nodes without the need for
 machine coordination. It uses configured node identifier to generate ids by
-attaching the node identifier to the end of the sequences.
+attaching the node identifier to the end of the sequences. The monoton/node
+package provides strategies to derive the node identifiers from the host.
 
 # Extendable
 
-The package comes with three pre-configured sequencers and Sequencer
+The package comes with four pre-configured sequencers and Sequencer
 interface to allow new sequencers.
 
 # Included Sequencers and Byte Orderings
 
-The monoton package currently comes with Nanosecond, Millisecond and
-Second sequencers. And it uses Millisecond sequencer by default. For each
-sequencer, the byte orders are as following:
+The monoton package currently comes with Nanosecond, Microsecond,
+Millisecond and Second sequencers. And it uses Millisecond sequencer by
+default. For each sequencer, the byte orders are as following:
 
 	Second:      16 B =>  6 B (seconds)      + 6 B (counter) + 4 B (node)
 	Millisecond: 16 B =>  8 B (milliseconds) + 4 B (counter) + 4 B (node)
+	Microsecond: 16 B => 10 B (microseconds) + 3 B (counter) + 3 B (node)
 	Nanosecond:  16 B => 11 B (nanoseconds)  + 2 B (counter) + 3 B (node)
 
 # New Sequencers
@@ -74,8 +83,8 @@ Example using Singleton
 	}
 
 	func newIDGenerator() monoton.Monoton {
-		// Fetch your node id from a config server or generate from MAC/IP
-		// address
+		// Fetch your node id from a config server or derive it from the host
+		// with the monoton/node package
 		node := uint64(1)
 
 		// A unix time value which will be subtracted from the time sequence
@@ -116,6 +125,9 @@ package monoton
 
 import (
 	"fmt"
+	"math"
+	"math/bits"
+	"time"
 
 	"github.com/mustafaturan/monoton/v3/encoder"
 	"github.com/mustafaturan/monoton/v3/sequencer"
@@ -127,6 +139,14 @@ const (
 	errMaxByteSize = "max byte size sum of sequence(%d) and time sequence(%d) " +
 		"can't be >= total byte size(%d), " +
 		"at least 1 byte slot is needed for node"
+	errByteSize    = "byte size must be %d (given %d)"
+	errMaxTime     = "time can't be greater than %d (given %d)"
+	errMaxSequence = "sequence can't be greater than %d (given %d)"
+	errTimeRange   = "time %d can't be represented with unit %s"
+	errMinTime     = "time can't be less than the initial time %d (given %d)"
+	errInitialTime = "initial time can't be greater than the current time %d " +
+		"(given %d)"
+	errEpoch = "time %s is before the unix epoch"
 )
 
 // MaxNodeCapacityExceededError is an error type with node information
@@ -155,18 +175,143 @@ func (e *MaxByteSizeError) Error() string {
 	)
 }
 
+// InvalidByteSizeError is an error type with the given and the expected byte
+// sizes
+type InvalidByteSizeError struct {
+	ByteSize      int
+	ByteSizeTotal int
+}
+
+func (e *InvalidByteSizeError) Error() string {
+	return fmt.Sprintf(errByteSize, e.ByteSizeTotal, e.ByteSize)
+}
+
+// MaxTimeExceededError is an error type with time information
+type MaxTimeExceededError struct {
+	Time    uint64
+	MaxTime uint64
+}
+
+func (e *MaxTimeExceededError) Error() string {
+	return fmt.Sprintf(errMaxTime, e.MaxTime, e.Time)
+}
+
+// MaxSequenceExceededError is an error type with sequence information
+type MaxSequenceExceededError struct {
+	Sequence    uint64
+	MaxSequence uint64
+}
+
+func (e *MaxSequenceExceededError) Error() string {
+	return fmt.Sprintf(errMaxSequence, e.MaxSequence, e.Sequence)
+}
+
+// InitialTimeExceededError is an error type with the time information which is
+// before the initial time
+type InitialTimeExceededError struct {
+	Time        uint64
+	InitialTime uint64
+}
+
+func (e *InitialTimeExceededError) Error() string {
+	return fmt.Sprintf(errMinTime, e.InitialTime, e.Time)
+}
+
+// InvalidInitialTimeError is an error type with the initial time which is in
+// the future of the sequencer time
+type InvalidInitialTimeError struct {
+	InitialTime uint64
+	Time        uint64
+}
+
+func (e *InvalidInitialTimeError) Error() string {
+	return fmt.Sprintf(errInitialTime, e.Time, e.InitialTime)
+}
+
+// EpochError is an error type with the time which is before the unix epoch
+type EpochError struct {
+	Time time.Time
+}
+
+func (e *EpochError) Error() string {
+	return fmt.Sprintf(errEpoch, e.Time.Format(time.RFC3339Nano))
+}
+
+// TimeRangeError is an error type with the time sequence value and the unit
+// which can't be represented as time.Time
+type TimeRangeError struct {
+	Time uint64
+	Unit time.Duration
+}
+
+func (e *TimeRangeError) Error() string {
+	return fmt.Sprintf(errTimeRange, e.Time, e.Unit)
+}
+
+// Components holds the decoded values of an identifier
+type Components struct {
+	// Time is the time sequence value including the initial time
+	Time uint64
+	// Counter is the sequence value for the time
+	Counter uint64
+	// Node is the node identifier
+	Node uint64
+}
+
+// Inspection is the decoded values of an identifier with its creation time
+type Inspection struct {
+	ID      string    `json:"id"`
+	Time    time.Time `json:"time"`
+	Counter uint64    `json:"counter"`
+	Node    uint64    `json:"node"`
+}
+
+// nower is implemented by the sequencers which can report their current time
+// value without generating a sequence
+type nower interface {
+	Now() uint64
+}
+
 // Monoton is a sequential id generator
 type Monoton struct {
 	initialTime     uint64
 	timeSeqByteSize int
 	seqByteSize     int
 	sequencer       sequencer.Sequencer
+	encoding        encoder.Encoding
 	node            []byte
 }
 
+// Option configures the optional behaviours of a Monoton
+type Option func(*Monoton)
+
+// WithEncoding sets the text encoding of the identifiers, the byte sizes are
+// computed with the encoding, so the sequencer's max values must fit into 16
+// bytes in the encoding. encoder.Base62 is used by default.
+//
+// The pre-configured sequencers are laid out for Base62 and New returns a
+// *MaxByteSizeError for them with the other encodings, the sequencers of the
+// other encodings are created by sequencer.NewCustom with Config.Encoding.
+func WithEncoding(e encoder.Encoding) Option {
+	return func(m *Monoton) {
+		m.encoding = e
+	}
+}
+
 // New inits a new monoton ID generator with the given generator and node.
-func New(s sequencer.Sequencer, node, initialTime uint64) (Monoton, error) {
-	m := Monoton{sequencer: s, initialTime: initialTime}
+func New(
+	s sequencer.Sequencer,
+	node, initialTime uint64,
+	opts ...Option,
+) (Monoton, error) {
+	m := Monoton{
+		sequencer:   s,
+		initialTime: initialTime,
+		encoding:    encoder.Base62,
+	}
+	for _, opt := range opts {
+		opt(&m)
+	}
 
 	if err := m.configureByteSizes(); err != nil {
 		return Monoton{}, err
@@ -176,6 +321,10 @@ func New(s sequencer.Sequencer, node, initialTime uint64) (Monoton, error) {
 		return Monoton{}, err
 	}
 
+	if err := m.validateInitialTime(); err != nil {
+		return Monoton{}, err
+	}
+
 	return m, nil
 }
 
@@ -184,6 +333,7 @@ func New(s sequencer.Sequencer, node, initialTime uint64) (Monoton, error) {
 //
 //	Second:      16 B =>  6 B (seconds)      + 6 B (counter) + 4 B (node)
 //	Millisecond: 16 B =>  8 B (milliseconds) + 4 B (counter) + 4 B (node)
+//	Microsecond: 16 B => 10 B (microseconds) + 3 B (counter) + 3 B (node)
 //	Nanosecond:  16 B => 11 B (nanoseconds)  + 2 B (counter) + 3 B (node)
 //
 // For byte size decisions please refer to docs/adrs/byte-sizes.md
@@ -198,32 +348,379 @@ func (m Monoton) Next() string {
 //
 //	Second:      16 B =>  6 B (seconds)      + 6 B (counter) + 4 B (node)
 //	Millisecond: 16 B =>  8 B (milliseconds) + 4 B (counter) + 4 B (node)
+//	Microsecond: 16 B => 10 B (microseconds) + 3 B (counter) + 3 B (node)
 //	Nanosecond:  16 B => 11 B (nanoseconds)  + 2 B (counter) + 3 B (node)
 //
 // For byte size decisions please refer to docs/adrs/byte-sizes.md
 func (m Monoton) NextBytes() [16]byte {
 	t, seq := m.sequencer.Next()
+	return m.encode(t-m.initialTime, seq)
+}
+
+// AppendNext appends the next incremental unique identifier as Base62 to dst
+// and returns the extended buffer. It doesn't allocate when dst has at least
+// 16 bytes of free capacity.
+func (m Monoton) AppendNext(dst []byte) []byte {
+	t, seq := m.sequencer.Next()
+	dst = m.encoding.AppendPadded(dst, t-m.initialTime, m.timeSeqByteSize)
+	dst = m.encoding.AppendPadded(dst, seq, m.seqByteSize)
+	return append(dst, m.node...)
+}
+
+// NextN generates n incremental unique identifiers as Base62 which are strictly
+// ordered. The sequences are reserved in ranges when the sequencer implements
+// the sequencer.Reserver interface.
+func (m Monoton) NextN(n int) []string {
+	ids := make([][totalByteSize]byte, n)
+	m.NextBytesN(ids)
+
+	// A single string backs all identifiers to allocate once
+	buf := make([]byte, 0, n*totalByteSize)
+	for i := range ids {
+		buf = append(buf, ids[i][:]...)
+	}
+	all := string(buf)
+
+	vals := make([]string, n)
+	for i := range vals {
+		vals[i] = all[i*totalByteSize : (i+1)*totalByteSize]
+	}
+	return vals
+}
+
+// NextBytesN fills the dst with incremental unique identifiers as Base62 16
+// bytes arrays which are strictly ordered. The sequences are reserved in
+// ranges when the sequencer implements the sequencer.Reserver interface.
+func (m Monoton) NextBytesN(dst [][16]byte) {
+	r, ok := m.sequencer.(sequencer.Reserver)
+	if !ok {
+		for i := range dst {
+			dst[i] = m.NextBytes()
+		}
+		return
+	}
+
+	for i := 0; i < len(dst); {
+		t, seq, count := r.Reserve(uint64(len(dst) - i))
+		i += m.encodeRange(dst[i:], t-m.initialTime, seq, count)
+	}
+}
+
+// TryNextBytesN fills the dst with identifiers like NextBytesN, but stops at
+// the first error and returns it with the count of the generated identifiers.
+// The sequences are reserved in ranges when the sequencer implements the
+// sequencer.TryReserver interface.
+func (m Monoton) TryNextBytesN(dst [][16]byte) (int, error) {
+	r, ok := m.sequencer.(sequencer.TryReserver)
+	if !ok {
+		for i := range dst {
+			id, err := m.TryNextBytes()
+			if err != nil {
+				return i, err
+			}
+			dst[i] = id
+		}
+		return len(dst), nil
+	}
+
+	for i := 0; i < len(dst); {
+		t, seq, count, err := r.TryReserve(uint64(len(dst) - i))
+		if err != nil {
+			return i, err
+		}
+		// The range is valid when its last sequence is
+		if t, err = m.validate(t, seq+count-1); err != nil {
+			return i, err
+		}
+		i += m.encodeRange(dst[i:], t, seq, count)
+	}
+	return len(dst), nil
+}
+
+// TryNext generates next incremental unique identifier as Base62 like Next,
+// but returns an error instead of a corrupt identifier when the counter is
+// exhausted, the time exceeds MaxTime or the time is before the initial time
+func (m Monoton) TryNext() (string, error) {
+	val, err := m.TryNextBytes()
+	if err != nil {
+		return "", err
+	}
+	return string(val[:]), nil
+}
+
+// TryNextBytes generates next incremental unique identifier as Base62 16
+// bytes array like NextBytes, but returns an error instead of a corrupt
+// identifier
+func (m Monoton) TryNextBytes() ([16]byte, error) {
+	t, seq, err := m.tryNext()
+	if err != nil {
+		return [totalByteSize]byte{}, err
+	}
+	return m.encode(t, seq), nil
+}
+
+// tryNext returns the next time sequence value after subtracting the initial
+// time with the sequence after validating both of them
+func (m Monoton) tryNext() (uint64, uint64, error) {
+	var t, seq uint64
+	if s, ok := m.sequencer.(sequencer.TrySequencer); ok {
+		var err error
+		if t, seq, err = s.TryNext(); err != nil {
+			return 0, 0, err
+		}
+	} else {
+		t, seq = m.sequencer.Next()
+	}
+	t, err := m.validate(t, seq)
+	if err != nil {
+		return 0, 0, err
+	}
+	return t, seq, nil
+}
+
+// validate returns the time sequence value after subtracting the initial time
+// or an error when the time or the sequence is out of range
+func (m Monoton) validate(t, seq uint64) (uint64, error) {
+	if maxSeq := m.sequencer.Max(); seq > maxSeq {
+		return 0, &MaxSequenceExceededError{
+			Sequence:    seq,
+			MaxSequence: maxSeq,
+		}
+	}
+	if t < m.initialTime {
+		return 0, &InitialTimeExceededError{
+			Time:        t,
+			InitialTime: m.initialTime,
+		}
+	}
+	if maxTime := m.sequencer.MaxTime(); t-m.initialTime > maxTime {
+		return 0, &MaxTimeExceededError{
+			Time:    t - m.initialTime,
+			MaxTime: maxTime,
+		}
+	}
+	return t - m.initialTime, nil
+}
+
+// encode places the encoded representations of the time sequence value, the
+// sequence and the node into the 16 bytes array
+func (m Monoton) encode(t, seq uint64) [16]byte {
+	// The calls through the Encoding interface move the array to heap, so the
+	// default encoding is called directly to keep the generation allocation
+	// free
+	if m.encoding != encoder.Base62 {
+		return m.encodeWith(t, seq)
+	}
+
 	var n [totalByteSize]byte
-	copy(
-		n[0:m.timeSeqByteSize],
-		encoder.ToBase62WithPaddingZeros(t-m.initialTime, m.timeSeqByteSize),
-	)
-	copy(
-		n[m.timeSeqByteSize:m.timeSeqByteSize+m.seqByteSize],
-		encoder.ToBase62WithPaddingZeros(seq, m.seqByteSize),
-	)
-	copy(
-		n[m.timeSeqByteSize+m.seqByteSize:],
-		m.node,
+	encoder.AppendBase62Padded(n[:0], t, m.timeSeqByteSize)
+	encoder.AppendBase62Padded(
+		n[m.timeSeqByteSize:m.timeSeqByteSize],
+		seq,
+		m.seqByteSize,
 	)
+	copy(n[m.timeSeqByteSize+m.seqByteSize:], m.node)
+	return n
+}
 
+// encodeRange fills the dst with count identifiers of the time sequence value
+// and the sequences from seq, and returns the count
+func (m Monoton) encodeRange(dst [][16]byte, t, seq, count uint64) int {
+	// The time and the node are encoded once for the range
+	id := m.encode(t, seq)
+	seqFrom := m.timeSeqByteSize
+	for j := uint64(0); j < count; j++ {
+		dst[j] = id
+		m.encoding.AppendPadded(dst[j][seqFrom:seqFrom], seq+j, m.seqByteSize)
+	}
+	return int(count)
+}
+
+// encodeWith is the encode of the other encodings
+func (m Monoton) encodeWith(t, seq uint64) [16]byte {
+	var n [totalByteSize]byte
+	m.encoding.AppendPadded(n[:0], t, m.timeSeqByteSize)
+	m.encoding.AppendPadded(
+		n[m.timeSeqByteSize:m.timeSeqByteSize],
+		seq,
+		m.seqByteSize,
+	)
+	copy(n[m.timeSeqByteSize+m.seqByteSize:], m.node)
 	return n
 }
 
+// Expiry returns the remaining duration until the time sequence value exceeds
+// the MaxTime of the sequencer
+func (m Monoton) Expiry() time.Duration {
+	var elapsed uint64
+	if now := m.now(); now > m.initialTime {
+		elapsed = now - m.initialTime
+	}
+
+	maxTime := m.sequencer.MaxTime()
+	if elapsed >= maxTime {
+		return 0
+	}
+
+	hi, lo := bits.Mul64(maxTime-elapsed, uint64(m.sequencer.Unit()))
+	if hi != 0 || lo > math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(lo)
+}
+
+// Parse decodes the given Base62 identifier into its components using the
+// byte sizes of the configured sequencer
+func (m Monoton) Parse(id string) (Components, error) {
+	if len(id) != totalByteSize {
+		return Components{}, &InvalidByteSizeError{
+			ByteSize:      len(id),
+			ByteSizeTotal: totalByteSize,
+		}
+	}
+
+	var b [totalByteSize]byte
+	copy(b[:], id)
+	return m.ParseBytes(b)
+}
+
+// ParseBytes decodes the given Base62 16 bytes identifier into its components
+// using the byte sizes of the configured sequencer
+func (m Monoton) ParseBytes(id [16]byte) (Components, error) {
+	seqOffset := m.timeSeqByteSize
+	nodeOffset := m.timeSeqByteSize + m.seqByteSize
+
+	t, err := m.decode(id, 0, seqOffset)
+	if err != nil {
+		return Components{}, err
+	}
+	if maxTime := m.sequencer.MaxTime(); t > maxTime {
+		return Components{}, &MaxTimeExceededError{Time: t, MaxTime: maxTime}
+	}
+
+	seq, err := m.decode(id, seqOffset, nodeOffset)
+	if err != nil {
+		return Components{}, err
+	}
+	if maxSeq := m.sequencer.Max(); seq > maxSeq {
+		return Components{}, &MaxSequenceExceededError{
+			Sequence:    seq,
+			MaxSequence: maxSeq,
+		}
+	}
+
+	node, err := m.decode(id, nodeOffset, totalByteSize)
+	if err != nil {
+		return Components{}, err
+	}
+	if err := m.validateNode(node); err != nil {
+		return Components{}, err
+	}
+
+	absolute, carry := bits.Add64(t, m.initialTime, 0)
+	if carry != 0 {
+		return Components{}, &MaxTimeExceededError{
+			Time:    t,
+			MaxTime: math.MaxUint64 - m.initialTime,
+		}
+	}
+
+	return Components{Time: absolute, Counter: seq, Node: node}, nil
+}
+
+// Time returns the creation time of the given Base62 identifier using the unit
+// of the configured sequencer and the initial time
+func (m Monoton) Time(id string) (time.Time, error) {
+	c, err := m.Parse(id)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return m.toTime(c.Time)
+}
+
+// Inspect decodes the given Base62 identifier into its components with its
+// creation time in UTC
+func (m Monoton) Inspect(id string) (Inspection, error) {
+	c, err := m.Parse(id)
+	if err != nil {
+		return Inspection{}, err
+	}
+	t, err := m.toTime(c.Time)
+	if err != nil {
+		return Inspection{}, err
+	}
+	return Inspection{
+		ID:      id,
+		Time:    t.UTC(),
+		Counter: c.Counter,
+		Node:    c.Node,
+	}, nil
+}
+
+// toTime converts the time sequence value into time.Time without overflowing
+// the intermediate nanosecond values
+func (m Monoton) toTime(t uint64) (time.Time, error) {
+	unit := m.sequencer.Unit()
+	if unit <= 0 {
+		return time.Time{}, &TimeRangeError{Time: t, Unit: unit}
+	}
+
+	hi, lo := bits.Mul64(t, uint64(unit))
+	if hi >= uint64(time.Second) {
+		return time.Time{}, &TimeRangeError{Time: t, Unit: unit}
+	}
+	sec, nsec := bits.Div64(hi, lo, uint64(time.Second))
+	if sec > math.MaxInt64 {
+		return time.Time{}, &TimeRangeError{Time: t, Unit: unit}
+	}
+	return time.Unix(int64(sec), int64(nsec)), nil
+}
+
+// fromTime converts the time.Time into the time sequence value relative to the
+// initial time using the unit of the configured sequencer
+func (m Monoton) fromTime(t time.Time) (uint64, error) {
+	unit := m.sequencer.Unit()
+	if unit <= 0 {
+		return 0, &TimeRangeError{Unit: unit}
+	}
+
+	sec := t.Unix()
+	if sec < 0 {
+		return 0, &EpochError{Time: t}
+	}
+
+	maxTime := m.sequencer.MaxTime()
+	hi, lo := bits.Mul64(uint64(sec), uint64(time.Second))
+	lo, carry := bits.Add64(lo, uint64(t.Nanosecond()), 0)
+	hi += carry
+	if hi >= uint64(unit) {
+		return 0, &MaxTimeExceededError{Time: math.MaxUint64, MaxTime: maxTime}
+	}
+
+	v, _ := bits.Div64(hi, lo, uint64(unit))
+	if v < m.initialTime {
+		return 0, &InitialTimeExceededError{Time: v, InitialTime: m.initialTime}
+	}
+	if v-m.initialTime > maxTime {
+		return 0, &MaxTimeExceededError{Time: v - m.initialTime, MaxTime: maxTime}
+	}
+	return v - m.initialTime, nil
+}
+
+// decode converts the encoded part of the id into uint64 and reports the
+// invalid char positions relative to the id
+func (m Monoton) decode(id [totalByteSize]byte, from, to int) (uint64, error) {
+	u, err := m.encoding.Decode(id[from:to])
+	if e, ok := err.(*encoder.InvalidCharError); ok {
+		e.Position += from
+	}
+	return u, err
+}
+
 func (m *Monoton) configureByteSizes() error {
-	maxNodeSeqByteSize := encoder.Base62ByteSize(m.sequencer.MaxNode())
-	maxTimeSeqByteSize := encoder.Base62ByteSize(m.sequencer.MaxTime())
-	maxSeqByteSize := encoder.Base62ByteSize(m.sequencer.Max())
+	maxNodeSeqByteSize := m.encoding.ByteSize(m.sequencer.MaxNode())
+	maxTimeSeqByteSize := m.encoding.ByteSize(m.sequencer.MaxTime())
+	maxSeqByteSize := m.encoding.ByteSize(m.sequencer.Max())
 
 	// The sum is always 16 bytes
 	if maxTimeSeqByteSize+maxSeqByteSize+maxNodeSeqByteSize != totalByteSize {
@@ -245,7 +742,7 @@ func (m *Monoton) configureNode(node uint64) error {
 		return err
 	}
 
-	m.node = encoder.ToBase62WithPaddingZeros(node, m.nodeByteSize())
+	m.node = m.encoding.AppendPadded(nil, node, m.nodeByteSize())
 	return nil
 }
 
@@ -258,6 +755,30 @@ func (m Monoton) validateNode(node uint64) error {
 	return nil
 }
 
+func (m Monoton) validateInitialTime() error {
+	now := m.now()
+	if m.initialTime > now {
+		return &InvalidInitialTimeError{InitialTime: m.initialTime, Time: now}
+	}
+	if maxTime := m.sequencer.MaxTime(); now-m.initialTime > maxTime {
+		return &MaxTimeExceededError{Time: now - m.initialTime, MaxTime: maxTime}
+	}
+	return nil
+}
+
+// now returns the current time value of the sequencer, when the sequencer
+// can't report it, the wall clock is converted with the sequencer unit
+func (m Monoton) now() uint64 {
+	if s, ok := m.sequencer.(nower); ok {
+		return s.Now()
+	}
+	unit := m.sequencer.Unit()
+	if unit <= 0 {
+		unit = time.Nanosecond
+	}
+	return uint64(time.Now().UnixNano()) / uint64(unit)
+}
+
 func (m Monoton) nodeByteSize() int {
 	return totalByteSize - (m.timeSeqByteSize + m.seqByteSize)
 }
@@ -8,9 +8,13 @@ import (
 	"bytes"
 	"errors"
 	"math"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/mustafaturan/monoton/v3/encoder"
+	"github.com/mustafaturan/monoton/v3/mtimer"
 	"github.com/mustafaturan/monoton/v3/sequencer"
 )
 
@@ -57,6 +61,22 @@ func TestNew(t *testing.T) {
 			"",
 			uint64(0),
 		},
+		{
+			&smallSequencer{now: 5},
+			0,
+			uint64(6),
+			errors.New("initial time can't be greater than the current time 5 (given 6)"),
+			"",
+			uint64(6),
+		},
+		{
+			&smallSequencer{now: uint64(math.Pow(62, 5)) + 1},
+			0,
+			uint64(0),
+			errors.New("time can't be greater than 916132832 (given 916132833)"),
+			"",
+			uint64(0),
+		},
 	}
 
 	configureMsg := "New(%v, %d, %d) want: %v, got: %v"
@@ -96,6 +116,54 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestWithEncoding(t *testing.T) {
+	tests := []struct {
+		e       encoder.Encoding
+		pattern string
+	}{
+		{encoder.Base32, "^[0-9A-HJKMNP-TV-Z]{16}$"},
+		{encoder.Base36, "^[0-9a-z]{16}$"},
+	}
+
+	for _, test := range tests {
+		s, _ := sequencer.NewCustom(sequencer.Config{
+			Unit:         time.Millisecond,
+			TimeBytes:    10,
+			CounterBytes: 3,
+			NodeBytes:    3,
+			Encoding:     test.e,
+			Clock:        mtimer.NewFakeClock(time.Unix(1, 0)),
+		})
+		m, err := New(s, 1000, 0, WithEncoding(test.e))
+		if err != nil {
+			t.Fatalf("New() with base %d returned error: %v", test.e.Base(), err)
+		}
+
+		ids := m.NextN(3)
+		for i, id := range ids {
+			if !regexp.MustCompile(test.pattern).MatchString(id) {
+				t.Errorf("NextN() with base %d generated: %s", test.e.Base(), id)
+			}
+			if i > 0 && ids[i-1] >= id {
+				t.Errorf("NextN() with base %d: %s >= %s", test.e.Base(), ids[i-1], id)
+			}
+
+			c, err := m.Parse(id)
+			want := Components{Time: 1000, Counter: uint64(i), Node: 1000}
+			if err != nil || c != want {
+				t.Errorf("Parse(%s) want: %+v, got: %+v, err: %v", id, want, c, err)
+			}
+		}
+	}
+
+	t.Run("requires the sequencer to fit into 16 bytes", func(t *testing.T) {
+		_, err := New(sequencer.NewMillisecond(), 1, 0, WithEncoding(encoder.Base32))
+		if _, ok := err.(*MaxByteSizeError); !ok {
+			t.Errorf("New() want *MaxByteSizeError, got: %v", err)
+		}
+	})
+}
+
 func TestNext(t *testing.T) {
 	m, _ := New(&validSequencer{}, 3843, 0)
 	m1, m2 := m.Next(), m.Next()
@@ -140,6 +208,401 @@ func TestNextBytes(t *testing.T) {
 	})
 }
 
+func TestAppendNext(t *testing.T) {
+	m, _ := New(&validSequencer{}, 3843, 0)
+
+	t.Run("appends the next id", func(t *testing.T) {
+		got := m.AppendNext([]byte("id:"))
+		if want := "id:00000001000001zz"; string(got) != want {
+			t.Errorf("AppendNext() want: %s, got: %s", want, got)
+		}
+	})
+
+	t.Run("generates the same layout with NextBytes", func(t *testing.T) {
+		s := &fixedSequencer{time: 1, seq: 2}
+		m, _ := New(s, 3, 1)
+		want := m.NextBytes()
+		if got := m.AppendNext(nil); string(got) != string(want[:]) {
+			t.Errorf("AppendNext() want: %s, got: %s", want, got)
+		}
+	})
+
+	t.Run("does not allocate with enough capacity", func(t *testing.T) {
+		m, _ := New(sequencer.NewMillisecond(), 0, 0)
+		buf := make([]byte, 0, 16)
+		allocs := testing.AllocsPerRun(1000, func() {
+			buf = m.AppendNext(buf[:0])
+		})
+		if allocs != 0 {
+			t.Errorf("AppendNext() want 0 allocs, got: %f", allocs)
+		}
+	})
+
+	t.Run("does not allocate with NextBytes", func(t *testing.T) {
+		m, _ := New(sequencer.NewMillisecond(), 0, 0)
+		allocs := testing.AllocsPerRun(1000, func() {
+			m.NextBytes()
+		})
+		if allocs != 0 {
+			t.Errorf("NextBytes() want 0 allocs, got: %f", allocs)
+		}
+	})
+}
+
+func TestNextN(t *testing.T) {
+	t.Run("generates ordered ids in ranges", func(t *testing.T) {
+		s, _ := sequencer.NewCustom(sequencer.Config{
+			Unit:         time.Millisecond,
+			TimeBytes:    8,
+			CounterBytes: 1,
+			NodeBytes:    7,
+			Clock:        mtimer.NewFakeClock(time.Unix(1, 0)),
+		}, sequencer.WithOverflowPolicy(sequencer.OverflowBorrow))
+		m, _ := New(s, 5, 0)
+
+		ids := m.NextN(150)
+		if len(ids) != 150 {
+			t.Fatalf("NextN(150) want 150 ids, got: %d", len(ids))
+		}
+		wantFirst := []string{"000000G800000005", "000000G900000005", "000000GA00000005"}
+		for i, want := range wantFirst {
+			if got := ids[i*62]; got != want {
+				t.Errorf("NextN() id %d want: %s, got: %s", i*62, want, got)
+			}
+		}
+		for i := 1; i < len(ids); i++ {
+			if ids[i-1] >= ids[i] {
+				t.Errorf("NextN() should be strictly ordered: %s >= %s", ids[i-1], ids[i])
+			}
+		}
+		if next := m.Next(); next <= ids[len(ids)-1] {
+			t.Errorf("Next() after NextN() should be greater: %s", next)
+		}
+	})
+
+	t.Run("falls back to Next without reservations", func(t *testing.T) {
+		m, _ := New(&validSequencer{}, 3843, 0)
+		ids := m.NextN(3)
+		if len(ids) != 3 || ids[0] >= ids[1] || ids[1] >= ids[2] {
+			t.Errorf("NextN(3) should be strictly ordered, got: %v", ids)
+		}
+	})
+
+	t.Run("generates nothing for zero", func(t *testing.T) {
+		m, _ := New(sequencer.NewMillisecond(), 0, 0)
+		if ids := m.NextN(0); len(ids) != 0 {
+			t.Errorf("NextN(0) want no ids, got: %v", ids)
+		}
+	})
+}
+
+func TestNextBytesN(t *testing.T) {
+	m, _ := New(sequencer.NewNanosecond(), 1, 0)
+	ids := make([][16]byte, 10000)
+	m.NextBytesN(ids)
+
+	for i := 1; i < len(ids); i++ {
+		if bytes.Compare(ids[i-1][:], ids[i][:]) >= 0 {
+			t.Fatalf("NextBytesN() should be strictly ordered: %s >= %s", ids[i-1], ids[i])
+		}
+	}
+}
+
+func TestTryNextBytesN(t *testing.T) {
+	t.Run("generates ordered ids until the first error", func(t *testing.T) {
+		s, _ := sequencer.NewCustom(sequencer.Config{
+			Unit:         time.Millisecond,
+			TimeBytes:    8,
+			CounterBytes: 1,
+			NodeBytes:    7,
+			Clock:        mtimer.NewFakeClock(time.Unix(1, 0)),
+		}, sequencer.WithOverflowPolicy(sequencer.OverflowFail))
+		m, _ := New(s, 5, 0)
+
+		ids := make([][16]byte, 100)
+		n, err := m.TryNextBytesN(ids)
+		var overflowErr *sequencer.OverflowError
+		if n != 62 || !errors.As(err, &overflowErr) {
+			t.Fatalf("TryNextBytesN() want: 62, *OverflowError, got: %d, %v", n, err)
+		}
+		first, last := string(ids[0][:]), string(ids[61][:])
+		if first != "000000G800000005" || last != "000000G8z0000005" {
+			t.Errorf("TryNextBytesN() want: 000000G800000005..000000G8z0000005, got: %s..%s", first, last)
+		}
+		if ids[62] != [16]byte{} {
+			t.Errorf("TryNextBytesN() should stop at the error, got: %s", ids[62])
+		}
+	})
+
+	t.Run("falls back to TryNextBytes without reservations", func(t *testing.T) {
+		m, _ := New(&validSequencer{}, 3843, 0)
+		ids := make([][16]byte, 3)
+		n, err := m.TryNextBytesN(ids)
+		if n != 3 || err != nil ||
+			bytes.Compare(ids[0][:], ids[1][:]) >= 0 ||
+			bytes.Compare(ids[1][:], ids[2][:]) >= 0 {
+			t.Errorf("TryNextBytesN() should be strictly ordered, got: %s, %d, %v", ids, n, err)
+		}
+	})
+
+	t.Run("returns the errors of the fallback", func(t *testing.T) {
+		m, _ := New(&fixedSequencer{time: 1, seq: 0}, 1, 2)
+		want := "time can't be less than the initial time 2 (given 1)"
+		n, err := m.TryNextBytesN(make([][16]byte, 3))
+		if n != 0 || err == nil || err.Error() != want {
+			t.Errorf("TryNextBytesN() want: 0, %s, got: %d, %v", want, n, err)
+		}
+	})
+}
+
+func TestTryNext(t *testing.T) {
+	m, _ := New(&validSequencer{}, 3843, 0)
+	m1, err1 := m.TryNext()
+	m2, err2 := m.TryNext()
+
+	t.Run("generates greater sequences on each call", func(t *testing.T) {
+		if err1 != nil || err2 != nil || strings.Compare(m1, m2) >= 0 {
+			t.Errorf("TryNext(): %s, %v >= TryNext(): %s, %v", m1, err1, m2, err2)
+		}
+	})
+
+	t.Run("returns the errors of the sequencer", func(t *testing.T) {
+		want := errors.New("exhausted")
+		m, _ := New(&fixedSequencer{err: want}, 1, 0)
+		if got, err := m.TryNext(); err != want || got != "" {
+			t.Errorf("TryNext() want error: %v, got: %q, %v", want, got, err)
+		}
+	})
+}
+
+func TestTryNextBytes(t *testing.T) {
+	tests := []struct {
+		s           sequencer.Sequencer
+		initialTime uint64
+		want        string
+	}{
+		{
+			&fixedSequencer{time: 1, seq: 0},
+			2,
+			"time can't be less than the initial time 2 (given 1)",
+		},
+		{
+			&fixedSequencer{time: uint64(math.Pow(62, 8)) + 1, seq: 0},
+			1,
+			"time can't be greater than 218340105584895 (given 218340105584896)",
+		},
+		{
+			&fixedSequencer{time: 1, seq: uint64(math.Pow(62, 6))},
+			0,
+			"sequence can't be greater than 56800235583 (given 56800235584)",
+		},
+	}
+
+	for _, test := range tests {
+		m, _ := New(test.s, 1, test.initialTime)
+		got, err := m.TryNextBytes()
+		if err == nil || err.Error() != test.want || got != [16]byte{} {
+			t.Errorf("TryNextBytes() want error: %s, got: %s, %v", test.want, got, err)
+		}
+	}
+
+	t.Run("generates the same layout with NextBytes", func(t *testing.T) {
+		s := &fixedSequencer{time: 1, seq: 2}
+		m, _ := New(s, 3, 1)
+		want := m.NextBytes()
+		if got, err := m.TryNextBytes(); err != nil || got != want {
+			t.Errorf("TryNextBytes() want: %s, got: %s, %v", want, got, err)
+		}
+	})
+}
+
+func TestExpiry(t *testing.T) {
+	t.Run("returns the remaining duration", func(t *testing.T) {
+		s := &smallSequencer{now: 100}
+		m, _ := New(s, 0, 10)
+		want := time.Duration(math.Pow(62, 5)-90) * time.Second
+		if got := m.Expiry(); got != want {
+			t.Errorf("Expiry() want: %s, got: %s", want, got)
+		}
+
+		s.now = uint64(math.Pow(62, 5)) + 10
+		if got := m.Expiry(); got != 0 {
+			t.Errorf("Expiry() want: 0, got: %s", got)
+		}
+	})
+
+	t.Run("saturates on overflows", func(t *testing.T) {
+		m, _ := New(sequencer.NewNanosecond(), 0, 0)
+		want := time.Duration(math.MaxInt64)
+		if got := m.Expiry(); got != want {
+			t.Errorf("Expiry() want: %s, got: %s", want, got)
+		}
+	})
+}
+
+func TestParse(t *testing.T) {
+	m, _ := New(&validSequencer{}, 3843, 10)
+
+	tests := []struct {
+		id   string
+		want Components
+	}{
+		{"0000000100000Azz", Components{Time: 11, Counter: 10, Node: 3843}},
+		{"zzzzzzzzzzzzzz00", Components{
+			Time:    uint64(math.Pow(62, 8)) + 9,
+			Counter: uint64(math.Pow(62, 6)) - 1,
+			Node:    0,
+		}},
+	}
+
+	msg := "Parse(%s) want: %+v, got: %+v"
+	for _, test := range tests {
+		got, err := m.Parse(test.id)
+		if err != nil {
+			t.Errorf("Parse(%s) returned error: %v", test.id, err)
+		}
+		if got != test.want {
+			t.Errorf(msg, test.id, test.want, got)
+		}
+	}
+
+	errorTests := []struct {
+		id   string
+		want string
+	}{
+		{"00000001", "byte size must be 16 (given 8)"},
+		{"0000000100000Azz0", "byte size must be 16 (given 17)"},
+		{"00000001-0000Azz", "invalid char '-' at position 8"},
+		{"0000000100000A+z", "invalid char '+' at position 14"},
+	}
+
+	msg = "Parse(%s) want error: %s, got: %v"
+	for _, test := range errorTests {
+		_, err := m.Parse(test.id)
+		if err == nil || err.Error() != test.want {
+			t.Errorf(msg, test.id, test.want, err)
+		}
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	m, _ := New(&validSequencer{}, 1, 0)
+	id := m.NextBytes()
+
+	t.Run("decodes generated ids", func(t *testing.T) {
+		got, err := m.ParseBytes(id)
+		want := Components{Time: 1, Counter: 1, Node: 1}
+		if err != nil || got != want {
+			t.Errorf("ParseBytes(%s) want: %+v, got: %+v, %v", id, want, got, err)
+		}
+	})
+
+	t.Run("errors on exceeded values", func(t *testing.T) {
+		s := &smallSequencer{}
+		m, _ := New(s, 0, 0)
+		tests := []struct {
+			id   string
+			want string
+		}{
+			{"zzzzzz0000000000", "time can't be greater than 916132832 (given 56800235583)"},
+			{"100000zzzzzzzzz0", "sequence can't be greater than 218340105584896 (given 13537086546263551)"},
+			{"1000001000000001", "node can't be greater than 0 (given 1)"},
+		}
+		for _, test := range tests {
+			var id [16]byte
+			copy(id[:], test.id)
+			_, err := m.ParseBytes(id)
+			if err == nil || err.Error() != test.want {
+				t.Errorf("ParseBytes(%s) want error: %s, got: %v", test.id, test.want, err)
+			}
+		}
+	})
+
+	t.Run("errors on overflowing times with the initial time", func(t *testing.T) {
+		const year2020asNanosecond = 1577836800000000000
+		m, _ := New(sequencer.NewNanosecond(), 1, year2020asNanosecond)
+		var id [16]byte
+		copy(id[:], "LygHa16AHYF00001")
+		_, err := m.ParseBytes(id)
+		if _, ok := err.(*MaxTimeExceededError); !ok {
+			t.Errorf("ParseBytes(%s) want *MaxTimeExceededError, got: %v", id, err)
+		}
+	})
+}
+
+func TestInspect(t *testing.T) {
+	const year2020asMillisecondPST = 1577865600000
+	m, _ := New(&validSequencer{}, 1, year2020asMillisecondPST)
+
+	t.Run("decodes the components with the time", func(t *testing.T) {
+		got, err := m.Inspect("0000000100000101")
+		want := Inspection{
+			ID:      "0000000100000101",
+			Time:    time.Date(2020, 1, 1, 8, 0, 0, int(time.Millisecond), time.UTC),
+			Counter: 1,
+			Node:    1,
+		}
+		if err != nil || got != want {
+			t.Errorf("Inspect() want: %+v, got: %+v, %v", want, got, err)
+		}
+	})
+
+	t.Run("errors on invalid ids", func(t *testing.T) {
+		want := "byte size must be 16 (given 1)"
+		if _, err := m.Inspect("0"); err == nil || err.Error() != want {
+			t.Errorf("Inspect() want error: %s, got: %v", want, err)
+		}
+	})
+}
+
+func TestTime(t *testing.T) {
+	const year2020asMillisecondPST = 1577865600000
+
+	t.Run("converts time sequence with the unit", func(t *testing.T) {
+		m, _ := New(&validSequencer{}, 1, year2020asMillisecondPST)
+		want := time.Date(2020, 1, 1, 8, 0, 0, int(time.Millisecond), time.UTC)
+		got, err := m.Time("0000000100000101")
+		if err != nil || !got.Equal(want) {
+			t.Errorf("Time() want: %s, got: %s, %v", want, got, err)
+		}
+	})
+
+	t.Run("converts generated ids of the real sequencers", func(t *testing.T) {
+		start := time.Now()
+		seqs := []sequencer.Sequencer{
+			sequencer.NewSecond(),
+			sequencer.NewMillisecond(),
+			sequencer.NewMicrosecond(),
+			sequencer.NewNanosecond(),
+		}
+		for _, s := range seqs {
+			m, _ := New(s, 1, 0)
+			before := start.Truncate(s.Unit())
+			got, err := m.Time(m.Next())
+			after := time.Now()
+			if err != nil || got.Before(before) || got.After(after) {
+				t.Errorf("Time() want between %s and %s, got: %s, %v", before, after, got, err)
+			}
+		}
+	})
+
+	t.Run("errors on invalid ids", func(t *testing.T) {
+		m, _ := New(&validSequencer{}, 1, 0)
+		want := "byte size must be 16 (given 1)"
+		if _, err := m.Time("0"); err == nil || err.Error() != want {
+			t.Errorf("Time() want error: %s, got: %v", want, err)
+		}
+	})
+
+	t.Run("errors on unrepresentable time", func(t *testing.T) {
+		m, _ := New(&smallSequencer{now: 1 << 63}, 0, 1<<63)
+		want := "time 9223372036854775808 can't be represented with unit 1s"
+		if _, err := m.Time("0000000000000000"); err == nil || err.Error() != want {
+			t.Errorf("Time() want error: %s, got: %v", want, err)
+		}
+	})
+}
+
 type validSequencer struct {
 	counter uint64
 }
@@ -160,6 +623,10 @@ func (v *validSequencer) Max() uint64 {
 	return uint64(math.Pow(62, 6)) - 1
 }
 
+func (v *validSequencer) Unit() time.Duration {
+	return time.Millisecond
+}
+
 func (v *validSequencer) Next() (uint64, uint64) {
 	v.counter++
 	return 1, v.counter
@@ -177,6 +644,53 @@ func (i *invalidSequencer) Max() uint64 {
 	return uint64(math.Pow(62, 8)) - 1
 }
 
+func (i *invalidSequencer) Unit() time.Duration {
+	return time.Millisecond
+}
+
 func (i *invalidSequencer) Next() (uint64, uint64) {
 	return 1, i.counter
 }
+
+type smallSequencer struct {
+	now uint64
+}
+
+func (s *smallSequencer) Now() uint64 {
+	return s.now
+}
+
+func (s *smallSequencer) MaxNode() uint64 {
+	return 0
+}
+
+func (s *smallSequencer) MaxTime() uint64 {
+	return uint64(math.Pow(62, 5))
+}
+
+func (s *smallSequencer) Max() uint64 {
+	return uint64(math.Pow(62, 8))
+}
+
+func (s *smallSequencer) Unit() time.Duration {
+	return time.Second
+}
+
+func (s *smallSequencer) Next() (uint64, uint64) {
+	return 0, 0
+}
+
+type fixedSequencer struct {
+	validSequencer
+	time uint64
+	seq  uint64
+	err  error
+}
+
+func (f *fixedSequencer) Next() (uint64, uint64) {
+	return f.time, f.seq
+}
+
+func (f *fixedSequencer) TryNext() (uint64, uint64, error) {
+	return f.time, f.seq, f.err
+}
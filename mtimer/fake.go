@@ -0,0 +1,73 @@
+package mtimer
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock is a Clock which can be set, advanced and frozen to generate
+// deterministic time values in tests
+type FakeClock struct {
+	mu     sync.Mutex
+	now    uint64
+	since  time.Time
+	frozen bool
+}
+
+// NewFakeClock inits a frozen fake clock at the given time, Unfreeze lets the
+// clock run with the real elapsed time
+func NewFakeClock(t time.Time) *FakeClock {
+	return &FakeClock{now: uint64(t.UnixNano()), frozen: true}
+}
+
+// Now returns the current fake time in nanoseconds
+func (c *FakeClock) Now() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.current()
+}
+
+// Set moves the clock to the given time, the clock can go backwards
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = uint64(t.UnixNano())
+	c.since = time.Now()
+}
+
+// Advance moves the clock by the given duration, negative durations move the
+// clock backwards
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now += uint64(d)
+}
+
+// Freeze stops the clock, it only moves with Set and Advance calls until it is
+// unfrozen
+func (c *FakeClock) Freeze() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.current()
+	c.frozen = true
+}
+
+// Unfreeze resumes the clock from its current time
+func (c *FakeClock) Unfreeze() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.since = time.Now()
+	c.frozen = false
+}
+
+func (c *FakeClock) current() uint64 {
+	if c.frozen {
+		return c.now
+	}
+	return c.now + uint64(time.Since(c.since))
+}
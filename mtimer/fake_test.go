@@ -0,0 +1,71 @@
+package mtimer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	startNano := uint64(start.UnixNano())
+
+	t.Run("starts frozen at the given time", func(t *testing.T) {
+		c := NewFakeClock(start)
+		time.Sleep(time.Millisecond)
+		if got := c.Now(); got != startNano {
+			t.Errorf("Now() want: %d, got: %d", startNano, got)
+		}
+	})
+
+	t.Run("runs from the given time", func(t *testing.T) {
+		c := NewFakeClock(start)
+		c.Unfreeze()
+		t1 := c.Now()
+		time.Sleep(time.Millisecond)
+		t2 := c.Now()
+		if t1 < startNano || t2 <= t1 {
+			t.Errorf("Now() should run from %d, got: %d, %d", startNano, t1, t2)
+		}
+	})
+
+	t.Run("freezes and unfreezes", func(t *testing.T) {
+		c := NewFakeClock(start)
+		c.Unfreeze()
+		time.Sleep(time.Millisecond)
+		c.Freeze()
+		t1 := c.Now()
+		time.Sleep(time.Millisecond)
+		if t2 := c.Now(); t1 != t2 {
+			t.Errorf("Now() should not change after Freeze(), got: %d, %d", t1, t2)
+		}
+
+		c.Unfreeze()
+		time.Sleep(time.Millisecond)
+		if t2 := c.Now(); t2 <= t1 {
+			t.Errorf("Now() should run after Unfreeze(), got: %d, %d", t1, t2)
+		}
+	})
+
+	t.Run("sets and advances", func(t *testing.T) {
+		c := NewFakeClock(time.Now())
+		c.Set(start)
+		if got := c.Now(); got != startNano {
+			t.Errorf("Set() want: %d, got: %d", startNano, got)
+		}
+
+		c.Advance(time.Second)
+		if got, want := c.Now(), startNano+uint64(time.Second); got != want {
+			t.Errorf("Advance() want: %d, got: %d", want, got)
+		}
+
+		c.Advance(-2 * time.Second)
+		if got, want := c.Now(), startNano-uint64(time.Second); got != want {
+			t.Errorf("Advance() backwards want: %d, got: %d", want, got)
+		}
+	})
+}
+
+func TestClock(t *testing.T) {
+	var _ Clock = New()
+	var _ Clock = NewFakeClock(time.Now())
+}
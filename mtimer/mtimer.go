@@ -5,7 +5,12 @@ import (
 	"time"
 )
 
-// Timer is a monotonic time ticker
+// Clock is a source of the current monotonic time in nanoseconds
+type Clock interface {
+	Now() uint64
+}
+
+// Timer is a monotonic time ticker and the real Clock implementation
 type Timer struct {
 	initialTime   time.Time
 	monotonicTime uint64
@@ -0,0 +1,19 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package node
+
+import (
+	"errors"
+	"os"
+)
+
+var errUnsupported = errors.New("file leases are not supported on this platform")
+
+func tryLock(file *os.File) (bool, error) {
+	return false, errUnsupported
+}
@@ -0,0 +1,218 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package node
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	errLeaseNotFound = "lease of node %d not found"
+	errNoFreeNode    = "no free node up to %d"
+)
+
+// LeaseNotFoundError is an error type with the node which is not leased or
+// whose lease is expired
+type LeaseNotFoundError struct {
+	Node uint64
+}
+
+func (e *LeaseNotFoundError) Error() string {
+	return fmt.Sprintf(errLeaseNotFound, e.Node)
+}
+
+// NoFreeNodeError is an error type with the max node value
+type NoFreeNodeError struct {
+	MaxNode uint64
+}
+
+func (e *NoFreeNodeError) Error() string {
+	return fmt.Sprintf(errNoFreeNode, e.MaxNode)
+}
+
+// MemoryLeaser leases the nodes between the generators of a single process
+type MemoryLeaser struct {
+	mu     sync.Mutex
+	leases map[uint64]time.Time
+	now    func() time.Time
+}
+
+// NewMemoryLeaser inits an in-memory leaser
+func NewMemoryLeaser() *MemoryLeaser {
+	return &MemoryLeaser{leases: make(map[uint64]time.Time), now: time.Now}
+}
+
+// Acquire leases the lowest free or expired node up to the max node
+func (m *MemoryLeaser) Acquire(maxNode uint64, ttl time.Duration) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	for n := uint64(0); ; n++ {
+		if expiry, ok := m.leases[n]; !ok || !now.Before(expiry) {
+			m.leases[n] = now.Add(ttl)
+			return n, nil
+		}
+		if n == maxNode {
+			return 0, &NoFreeNodeError{MaxNode: maxNode}
+		}
+	}
+}
+
+// Renew extends the lease of the node if it is not expired
+func (m *MemoryLeaser) Renew(node uint64, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if expiry, ok := m.leases[node]; !ok || !now.Before(expiry) {
+		return &LeaseNotFoundError{Node: node}
+	}
+	m.leases[node] = now.Add(ttl)
+	return nil
+}
+
+// Release removes the lease of the node
+func (m *MemoryLeaser) Release(node uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.leases[node]; !ok {
+		return &LeaseNotFoundError{Node: node}
+	}
+	delete(m.leases, node)
+	return nil
+}
+
+// FileLeaser leases the nodes between the processes of a host with the file
+// locks in a shared directory. A lease is a `node-<n>.lease` file which is
+// exclusively locked while the lease is held, so the leases of the crashed
+// processes are released by the operating system. The expiry is written into
+// the file for inspection and the leases which are not renewed in time are
+// released by the leaser which holds them. The other processes only honour the
+// locks, so the ttl has no effect across the processes: a hung process keeps
+// its lease until it exits.
+type FileLeaser struct {
+	mu     sync.Mutex
+	dir    string
+	leases map[uint64]*fileLease
+	now    func() time.Time
+}
+
+type fileLease struct {
+	file   *os.File
+	expiry time.Time
+}
+
+// NewFileLeaser inits a file leaser in the given directory
+func NewFileLeaser(dir string) *FileLeaser {
+	return &FileLeaser{
+		dir:    dir,
+		leases: make(map[uint64]*fileLease),
+		now:    time.Now,
+	}
+}
+
+// Acquire leases the lowest node up to the max node whose file isn't locked
+func (f *FileLeaser) Acquire(maxNode uint64, ttl time.Duration) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := os.MkdirAll(f.dir, 0o755); err != nil {
+		return 0, err
+	}
+
+	for n := uint64(0); ; n++ {
+		if _, ok := f.leases[n]; !ok {
+			ok, err := f.lock(n, ttl)
+			if err != nil {
+				return 0, err
+			}
+			if ok {
+				return n, nil
+			}
+		}
+		if n == maxNode {
+			return 0, &NoFreeNodeError{MaxNode: maxNode}
+		}
+	}
+}
+
+// Renew extends the lease of the node if it is not expired, the expired
+// leases are released
+func (f *FileLeaser) Renew(node uint64, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	lease, ok := f.leases[node]
+	if !ok {
+		return &LeaseNotFoundError{Node: node}
+	}
+
+	now := f.now()
+	if !now.Before(lease.expiry) {
+		f.unlock(node)
+		return &LeaseNotFoundError{Node: node}
+	}
+	lease.expiry = now.Add(ttl)
+	return writeExpiry(lease.file, lease.expiry)
+}
+
+// Release unlocks the file of the node lease
+func (f *FileLeaser) Release(node uint64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.leases[node]; !ok {
+		return &LeaseNotFoundError{Node: node}
+	}
+	return f.unlock(node)
+}
+
+func (f *FileLeaser) lock(node uint64, ttl time.Duration) (bool, error) {
+	name := "node-" + strconv.FormatUint(node, 10) + ".lease"
+	file, err := os.OpenFile(
+		filepath.Join(f.dir, name),
+		os.O_CREATE|os.O_RDWR,
+		0o644,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	locked, err := tryLock(file)
+	if err != nil || !locked {
+		file.Close()
+		return false, err
+	}
+
+	lease := &fileLease{file: file, expiry: f.now().Add(ttl)}
+	if err := writeExpiry(file, lease.expiry); err != nil {
+		file.Close()
+		return false, err
+	}
+	f.leases[node] = lease
+	return true, nil
+}
+
+// unlock closes the lease file which releases its lock
+func (f *FileLeaser) unlock(node uint64) error {
+	lease := f.leases[node]
+	delete(f.leases, node)
+	return lease.file.Close()
+}
+
+func writeExpiry(file *os.File, expiry time.Time) error {
+	if err := file.Truncate(0); err != nil {
+		return err
+	}
+	_, err := file.WriteAt([]byte(expiry.UTC().Format(time.RFC3339Nano)), 0)
+	return err
+}
@@ -0,0 +1,120 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package node
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3"
+)
+
+var (
+	_ monoton.NodeLeaser = NewMemoryLeaser()
+	_ monoton.NodeLeaser = NewFileLeaser("")
+)
+
+func TestMemoryLeaser(t *testing.T) {
+	now := time.Now()
+	l := NewMemoryLeaser()
+	l.now = func() time.Time { return now }
+
+	t.Run("acquires the lowest free nodes", func(t *testing.T) {
+		for want := uint64(0); want < 2; want++ {
+			if got, err := l.Acquire(1, time.Second); err != nil || got != want {
+				t.Errorf("Acquire() want: %d, got: %d, %v", want, got, err)
+			}
+		}
+
+		want := "no free node up to 1"
+		if _, err := l.Acquire(1, time.Second); err == nil || err.Error() != want {
+			t.Errorf("Acquire() want error: %s, got: %v", want, err)
+		}
+	})
+
+	t.Run("renews and releases", func(t *testing.T) {
+		now = now.Add(500 * time.Millisecond)
+		if err := l.Renew(0, time.Second); err != nil {
+			t.Errorf("Renew() returned error: %v", err)
+		}
+		if err := l.Release(1); err != nil {
+			t.Errorf("Release() returned error: %v", err)
+		}
+		if got, err := l.Acquire(1, time.Second); err != nil || got != 1 {
+			t.Errorf("Acquire() after Release() want: 1, got: %d, %v", got, err)
+		}
+	})
+
+	t.Run("reuses the expired leases", func(t *testing.T) {
+		now = now.Add(1200 * time.Millisecond)
+		if got, err := l.Acquire(1, time.Second); err != nil || got != 0 {
+			t.Errorf("Acquire() want: 0, got: %d, %v", got, err)
+		}
+		now = now.Add(time.Second)
+		want := "lease of node 1 not found"
+		if err := l.Renew(1, time.Second); err == nil || err.Error() != want {
+			t.Errorf("Renew() want error: %s, got: %v", want, err)
+		}
+		if err := l.Release(5); err == nil {
+			t.Errorf("Release() should return error for unknown nodes")
+		}
+	})
+}
+
+func TestFileLeaser(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "leases")
+	l1, l2 := NewFileLeaser(dir), NewFileLeaser(dir)
+
+	t.Run("locks the nodes between leasers", func(t *testing.T) {
+		if got, err := l1.Acquire(1, time.Second); err != nil || got != 0 {
+			t.Errorf("Acquire() want: 0, got: %d, %v", got, err)
+		}
+		if got, err := l2.Acquire(1, time.Second); err != nil || got != 1 {
+			t.Errorf("Acquire() want: 1, got: %d, %v", got, err)
+		}
+		want := "no free node up to 1"
+		if _, err := l1.Acquire(1, time.Second); err == nil || err.Error() != want {
+			t.Errorf("Acquire() want error: %s, got: %v", want, err)
+		}
+	})
+
+	t.Run("writes the expiry", func(t *testing.T) {
+		data, err := os.ReadFile(filepath.Join(dir, "node-0.lease"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := time.Parse(time.RFC3339Nano, string(data)); err != nil {
+			t.Errorf("lease file should contain the expiry, got: %s", data)
+		}
+	})
+
+	t.Run("releases the locks", func(t *testing.T) {
+		if err := l2.Renew(1, time.Second); err != nil {
+			t.Errorf("Renew() returned error: %v", err)
+		}
+		if err := l2.Release(1); err != nil {
+			t.Errorf("Release() returned error: %v", err)
+		}
+		if got, err := l1.Acquire(1, time.Second); err != nil || got != 1 {
+			t.Errorf("Acquire() after Release() want: 1, got: %d, %v", got, err)
+		}
+		if err := l2.Release(1); err == nil {
+			t.Errorf("Release() should return error for released nodes")
+		}
+	})
+
+	t.Run("releases the expired leases on renew", func(t *testing.T) {
+		l1.now = func() time.Time { return time.Now().Add(time.Minute) }
+		want := "lease of node 0 not found"
+		if err := l1.Renew(0, time.Second); err == nil || err.Error() != want {
+			t.Errorf("Renew() want error: %s, got: %v", want, err)
+		}
+		if got, err := l2.Acquire(1, time.Second); err != nil || got != 0 {
+			t.Errorf("Acquire() after expiry want: 0, got: %d, %v", got, err)
+		}
+	})
+}
@@ -0,0 +1,198 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+// Package node provides strategies to derive the node identifiers of the
+// monoton generators from the host without a config server.
+//
+// All strategies accept the max node value, which is the MaxNode of the
+// sequencer. The derived strategies (MAC, IPv4, hostname) reduce the value
+// into the node space, so two hosts can still collide on small node spaces.
+// The exact strategies (environment variable, StatefulSet ordinal) return an
+// error when the value is greater than the max node.
+//
+//	s := sequencer.NewMillisecond()
+//	n, err := node.FromStatefulSetOrdinal(s.MaxNode())
+//	if err != nil {
+//		panic(err)
+//	}
+//	m, err := monoton.New(s, n, 0)
+package node
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mustafaturan/monoton/v3"
+)
+
+const (
+	errInterfaceNotFound = "network interface with a hardware address " +
+		"not found (given %q)"
+	errAddressNotFound = "non-loopback IPv4 address not found"
+	errEnvNotFound     = "environment variable %q is not set"
+	errInvalidValue    = "node value must be an unsigned integer (given %q)"
+	errOrdinalNotFound = "ordinal suffix not found in hostname %q"
+)
+
+// Used as variables to stub the host in tests
+var (
+	hostname       = os.Hostname
+	interfaces     = net.Interfaces
+	interfaceAddrs = net.InterfaceAddrs
+	lookupEnv      = os.LookupEnv
+)
+
+// InterfaceNotFoundError is an error type with the network interface name
+type InterfaceNotFoundError struct {
+	Name string
+}
+
+func (e *InterfaceNotFoundError) Error() string {
+	return fmt.Sprintf(errInterfaceNotFound, e.Name)
+}
+
+// AddressNotFoundError is an error type for the hosts without an IPv4 address
+type AddressNotFoundError struct{}
+
+func (e *AddressNotFoundError) Error() string {
+	return errAddressNotFound
+}
+
+// EnvNotFoundError is an error type with the environment variable name
+type EnvNotFoundError struct {
+	Key string
+}
+
+func (e *EnvNotFoundError) Error() string {
+	return fmt.Sprintf(errEnvNotFound, e.Key)
+}
+
+// InvalidValueError is an error type with the given node value
+type InvalidValueError struct {
+	Value string
+}
+
+func (e *InvalidValueError) Error() string {
+	return fmt.Sprintf(errInvalidValue, e.Value)
+}
+
+// OrdinalNotFoundError is an error type with the hostname
+type OrdinalNotFoundError struct {
+	Hostname string
+}
+
+func (e *OrdinalNotFoundError) Error() string {
+	return fmt.Sprintf(errOrdinalNotFound, e.Hostname)
+}
+
+// FromMAC derives the node from the hardware address of the named network
+// interface, or of the first non-loopback interface when the name is empty
+func FromMAC(name string, max uint64) (uint64, error) {
+	ifaces, err := interfaces()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, iface := range ifaces {
+		if len(iface.HardwareAddr) == 0 {
+			continue
+		}
+		if name == "" && iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		if name != "" && iface.Name != name {
+			continue
+		}
+
+		// The low bytes are the device specific part of the address
+		hw := iface.HardwareAddr
+		if len(hw) > 8 {
+			hw = hw[len(hw)-8:]
+		}
+		var b [8]byte
+		copy(b[8-len(hw):], hw)
+		return reduce(binary.BigEndian.Uint64(b[:]), max), nil
+	}
+	return 0, &InterfaceNotFoundError{Name: name}
+}
+
+// FromIPv4 derives the node from the low bits of the first non-loopback IPv4
+// address of the host
+func FromIPv4(max uint64) (uint64, error) {
+	addrs, err := interfaceAddrs()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() {
+			continue
+		}
+		if ip := ipNet.IP.To4(); ip != nil {
+			return reduce(uint64(binary.BigEndian.Uint32(ip)), max), nil
+		}
+	}
+	return 0, &AddressNotFoundError{}
+}
+
+// FromHostname derives the node from the FNV-1a hash of the hostname
+func FromHostname(max uint64) (uint64, error) {
+	name, err := hostname()
+	if err != nil {
+		return 0, err
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+	return reduce(h.Sum64(), max), nil
+}
+
+// FromEnv reads the node from the environment variable with the given key
+func FromEnv(key string, max uint64) (uint64, error) {
+	val, ok := lookupEnv(key)
+	if !ok {
+		return 0, &EnvNotFoundError{Key: key}
+	}
+	return parse(strings.TrimSpace(val), max)
+}
+
+// FromStatefulSetOrdinal reads the node from the ordinal suffix of the
+// hostname of a Kubernetes StatefulSet pod like `web-3`
+func FromStatefulSetOrdinal(max uint64) (uint64, error) {
+	name, err := hostname()
+	if err != nil {
+		return 0, err
+	}
+
+	i := strings.LastIndexByte(name, '-')
+	if i < 0 || i == len(name)-1 {
+		return 0, &OrdinalNotFoundError{Hostname: name}
+	}
+	return parse(name[i+1:], max)
+}
+
+func parse(val string, max uint64) (uint64, error) {
+	n, err := strconv.ParseUint(val, 10, 64)
+	if err != nil {
+		return 0, &InvalidValueError{Value: val}
+	}
+	if n > max {
+		return 0, &monoton.MaxNodeCapacityExceededError{Node: n, MaxNode: max}
+	}
+	return n, nil
+}
+
+// reduce maps the value into the node space
+func reduce(val, max uint64) uint64 {
+	if max == 1<<64-1 {
+		return val
+	}
+	return val % (max + 1)
+}
@@ -0,0 +1,155 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package node
+
+import (
+	"errors"
+	"hash/fnv"
+	"net"
+	"testing"
+)
+
+func TestFromMAC(t *testing.T) {
+	defer stubInterfaces([]net.Interface{
+		{Name: "lo", Flags: net.FlagLoopback},
+		{Name: "eth0", HardwareAddr: net.HardwareAddr{0, 0, 0, 0, 0x01, 0x02}},
+		{Name: "eth1", HardwareAddr: net.HardwareAddr{0, 0, 0, 0, 0x10, 0x00}},
+	})()
+
+	tests := []struct {
+		name string
+		max  uint64
+		want uint64
+	}{
+		{"", 1<<64 - 1, 0x0102},
+		{"eth0", 0xFF, 0x02},
+		{"eth1", 1<<64 - 1, 0x1000},
+	}
+
+	for _, test := range tests {
+		got, err := FromMAC(test.name, test.max)
+		if err != nil || got != test.want {
+			t.Errorf("FromMAC(%q, %d) want: %d, got: %d, %v", test.name, test.max, test.want, got, err)
+		}
+	}
+
+	want := `network interface with a hardware address not found (given "lo")`
+	if _, err := FromMAC("lo", 10); err == nil || err.Error() != want {
+		t.Errorf("FromMAC() want error: %s, got: %v", want, err)
+	}
+}
+
+func TestFromIPv4(t *testing.T) {
+	addrs := []net.Addr{
+		&net.IPNet{IP: net.IPv4(127, 0, 0, 1)},
+		&net.IPNet{IP: net.ParseIP("fe80::1")},
+		&net.IPNet{IP: net.IPv4(10, 0, 1, 2)},
+	}
+	defer stubInterfaceAddrs(addrs, nil)()
+
+	if got, err := FromIPv4(0xFFFF); err != nil || got != 0x0102 {
+		t.Errorf("FromIPv4() want: %d, got: %d, %v", 0x0102, got, err)
+	}
+
+	stubInterfaceAddrs(addrs[:2], nil)
+	want := "non-loopback IPv4 address not found"
+	if _, err := FromIPv4(0xFFFF); err == nil || err.Error() != want {
+		t.Errorf("FromIPv4() want error: %s, got: %v", want, err)
+	}
+
+	failure := errors.New("failure")
+	stubInterfaceAddrs(nil, failure)
+	if _, err := FromIPv4(0xFFFF); err != failure {
+		t.Errorf("FromIPv4() want error: %v, got: %v", failure, err)
+	}
+}
+
+func TestFromHostname(t *testing.T) {
+	defer stubHostname("web-1", nil)()
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte("web-1"))
+	want := h.Sum64() % 3844
+
+	if got, err := FromHostname(3843); err != nil || got != want {
+		t.Errorf("FromHostname() want: %d, got: %d, %v", want, got, err)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	env := map[string]string{"NODE": " 12 ", "INVALID": "x", "LARGE": "3844"}
+	original := lookupEnv
+	lookupEnv = func(key string) (string, bool) {
+		val, ok := env[key]
+		return val, ok
+	}
+	defer func() { lookupEnv = original }()
+
+	if got, err := FromEnv("NODE", 3843); err != nil || got != 12 {
+		t.Errorf("FromEnv() want: 12, got: %d, %v", got, err)
+	}
+
+	errorTests := []struct {
+		key  string
+		want string
+	}{
+		{"MISSING", `environment variable "MISSING" is not set`},
+		{"INVALID", `node value must be an unsigned integer (given "x")`},
+		{"LARGE", "node can't be greater than 3843 (given 3844)"},
+	}
+	for _, test := range errorTests {
+		if _, err := FromEnv(test.key, 3843); err == nil || err.Error() != test.want {
+			t.Errorf("FromEnv(%s) want error: %s, got: %v", test.key, test.want, err)
+		}
+	}
+}
+
+func TestFromStatefulSetOrdinal(t *testing.T) {
+	defer stubHostname("web-12", nil)()
+
+	if got, err := FromStatefulSetOrdinal(3843); err != nil || got != 12 {
+		t.Errorf("FromStatefulSetOrdinal() want: 12, got: %d, %v", got, err)
+	}
+
+	errorTests := []struct {
+		hostname string
+		want     string
+	}{
+		{"web", `ordinal suffix not found in hostname "web"`},
+		{"web-", `ordinal suffix not found in hostname "web-"`},
+		{"web-a", `node value must be an unsigned integer (given "a")`},
+		{"web-62", "node can't be greater than 61 (given 62)"},
+	}
+	for _, test := range errorTests {
+		stubHostname(test.hostname, nil)
+		if _, err := FromStatefulSetOrdinal(61); err == nil || err.Error() != test.want {
+			t.Errorf("FromStatefulSetOrdinal() with %s want error: %s, got: %v", test.hostname, test.want, err)
+		}
+	}
+
+	failure := errors.New("failure")
+	stubHostname("", failure)
+	if _, err := FromStatefulSetOrdinal(61); err != failure {
+		t.Errorf("FromStatefulSetOrdinal() want error: %v, got: %v", failure, err)
+	}
+}
+
+func stubInterfaces(ifaces []net.Interface) func() {
+	original := interfaces
+	interfaces = func() ([]net.Interface, error) { return ifaces, nil }
+	return func() { interfaces = original }
+}
+
+func stubInterfaceAddrs(addrs []net.Addr, err error) func() {
+	original := interfaceAddrs
+	interfaceAddrs = func() ([]net.Addr, error) { return addrs, err }
+	return func() { interfaceAddrs = original }
+}
+
+func stubHostname(name string, err error) func() {
+	original := hostname
+	hostname = func() (string, error) { return name, err }
+	return func() { hostname = original }
+}
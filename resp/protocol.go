@@ -0,0 +1,149 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package resp
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var lineBreaks = strings.NewReplacer("\r", " ", "\n", " ")
+
+const (
+	maxArgs    = 1024
+	maxBulkLen = 64 * 1024
+
+	errProtocol = "Protocol error: %s"
+)
+
+// ProtocolError is an error type with the reason of a malformed request, the
+// connection is closed after replying it
+type ProtocolError struct {
+	Reason string
+}
+
+func (e *ProtocolError) Error() string {
+	return fmt.Sprintf(errProtocol, e.Reason)
+}
+
+// readCommand reads one command either as an array of bulk strings or as an
+// inline command. An empty command is returned for the empty lines.
+func readCommand(r *bufio.Reader) ([][]byte, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 || line[0] != '*' {
+		return inlineCommand(line), nil
+	}
+
+	n, err := strconv.Atoi(string(line[1:]))
+	if err != nil || n > maxArgs {
+		return nil, &ProtocolError{Reason: "invalid multibulk length"}
+	}
+	if n <= 0 {
+		return nil, nil
+	}
+
+	args := make([][]byte, 0, n)
+	for i := 0; i < n; i++ {
+		line, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(line) == 0 || line[0] != '$' {
+			return nil, &ProtocolError{
+				Reason: fmt.Sprintf("expected '$', got '%s'", line),
+			}
+		}
+		size, err := strconv.Atoi(string(line[1:]))
+		if err != nil || size < 0 || size > maxBulkLen {
+			return nil, &ProtocolError{Reason: "invalid bulk length"}
+		}
+
+		arg := make([]byte, size+2)
+		if _, err := io.ReadFull(r, arg); err != nil {
+			return nil, err
+		}
+		if arg[size] != '\r' || arg[size+1] != '\n' {
+			return nil, &ProtocolError{Reason: "invalid bulk terminator"}
+		}
+		args = append(args, arg[:size])
+	}
+	return args, nil
+}
+
+// readLine reads a line without the CRLF or LF terminator, the returned slice
+// is only valid until the next read
+func readLine(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		return nil, &ProtocolError{Reason: "too big request"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	line = line[:len(line)-1]
+	if len(line) > 0 && line[len(line)-1] == '\r' {
+		line = line[:len(line)-1]
+	}
+	return line, nil
+}
+
+func inlineCommand(line []byte) [][]byte {
+	fields := bytes.Fields(line)
+	args := make([][]byte, len(fields))
+	for i, f := range fields {
+		args[i] = append([]byte(nil), f...)
+	}
+	return args
+}
+
+// writer appends the RESP replies into a buffered writer
+type writer struct {
+	w   *bufio.Writer
+	buf []byte
+}
+
+func (w *writer) simple(s string) {
+	w.w.WriteByte('+')
+	w.w.WriteString(s)
+	w.w.WriteString("\r\n")
+}
+
+// error writes the error as a simple error, the line breaks of the message
+// are replaced to never let it end the reply early
+func (w *writer) error(err error) {
+	w.w.WriteString("-ERR ")
+	w.w.WriteString(lineBreaks.Replace(err.Error()))
+	w.w.WriteString("\r\n")
+}
+
+func (w *writer) integer(n uint64) {
+	w.buf = append(w.buf[:0], ':')
+	w.buf = strconv.AppendUint(w.buf, n, 10)
+	w.buf = append(w.buf, '\r', '\n')
+	w.w.Write(w.buf)
+}
+
+func (w *writer) array(n int) {
+	w.buf = append(w.buf[:0], '*')
+	w.buf = strconv.AppendInt(w.buf, int64(n), 10)
+	w.buf = append(w.buf, '\r', '\n')
+	w.w.Write(w.buf)
+}
+
+func (w *writer) bulk(b []byte) {
+	w.buf = append(w.buf[:0], '$')
+	w.buf = strconv.AppendInt(w.buf, int64(len(b)), 10)
+	w.buf = append(w.buf, '\r', '\n')
+	w.buf = append(w.buf, b...)
+	w.buf = append(w.buf, '\r', '\n')
+	w.w.Write(w.buf)
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package resp
+
+import (
+	"bufio"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadCommand(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"*1\r\n$6\r\nNEXTID\r\n", []string{"NEXTID"}},
+		{"*2\r\n$7\r\nNEXTIDS\r\n$2\r\n10\r\n", []string{"NEXTIDS", "10"}},
+		{"*2\r\n$7\r\nINSPECT\r\n$0\r\n\r\n", []string{"INSPECT", ""}},
+		{"nextids  10\r\n", []string{"nextids", "10"}},
+		{"PING\n", []string{"PING"}},
+		{"\r\n", []string{}},
+		{"*0\r\n", nil},
+	}
+
+	for _, test := range tests {
+		got, err := readCommand(bufio.NewReader(strings.NewReader(test.input)))
+		if err != nil {
+			t.Errorf("readCommand(%q) returned error: %v", test.input, err)
+			continue
+		}
+		args := make([]string, len(got))
+		for i, arg := range got {
+			args[i] = string(arg)
+		}
+		if len(args) != len(test.want) || (len(args) > 0 && !reflect.DeepEqual(args, test.want)) {
+			t.Errorf("readCommand(%q) want: %q, got: %q", test.input, test.want, args)
+		}
+	}
+
+	errorTests := []struct {
+		input   string
+		wantErr string
+	}{
+		{"*x\r\n", "Protocol error: invalid multibulk length"},
+		{"*1025\r\n", "Protocol error: invalid multibulk length"},
+		{"*1\r\n:1\r\n", "Protocol error: expected '$', got ':1'"},
+		{"*1\r\n$-1\r\n", "Protocol error: invalid bulk length"},
+		{"*1\r\n$65537\r\n", "Protocol error: invalid bulk length"},
+		{"*1\r\n$2\r\nabc\r\n", "Protocol error: invalid bulk terminator"},
+		{strings.Repeat("a", 5000) + "\r\n", "Protocol error: too big request"},
+		{"*1\r\n$6\r\nNEXT", io.ErrUnexpectedEOF.Error()},
+		{"", io.EOF.Error()},
+	}
+
+	for _, test := range errorTests {
+		_, err := readCommand(bufio.NewReader(strings.NewReader(test.input)))
+		if err == nil || err.Error() != test.wantErr {
+			t.Errorf("readCommand(%.20q) want err: %s, got: %v", test.input, test.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+/*
+Package resp provides a TCP server which speaks the Redis serialization
+protocol (RESP2), so the identifiers of one monoton generator can be fetched
+with any Redis client.
+
+The commands are:
+
+	NEXTID         replies an identifier as a bulk string
+	NEXTIDS n      replies n strictly ordered identifiers as an array
+	INSPECT id     replies the time, counter and node of an identifier
+	PING           replies PONG
+	QUIT           replies OK and closes the connection
+
+The commands can be pipelined, the replies are flushed when there are no more
+buffered commands on the connection. The connections over the limit are
+replied with an error and closed.
+
+	m, err := monoton.New(sequencer.NewMillisecond(), node, initialTime)
+	if err != nil {
+		panic(err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:6380")
+	if err != nil {
+		panic(err)
+	}
+	err = resp.New(m).Serve(ctx, l)
+
+	$ redis-cli -p 6380 NEXTIDS 2
+	1) "0VYNMoYY0000000J"
+	2) "0VYNMoYY0001000J"
+*/
+package resp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3"
+	"github.com/mustafaturan/monoton/v3/internal/netserve"
+)
+
+const (
+	defaultMaxConns = 1024
+	defaultMaxBatch = 1000
+
+	errUnknownCommand = "unknown command %q"
+	errArgs           = "wrong number of arguments for '%s' command"
+	errCount          = "n must be between 1 and %d"
+	errMaxClients     = "-ERR max number of clients reached\r\n"
+)
+
+// Server is a RESP server which serves the identifiers of a generator
+type Server struct {
+	monoton  monoton.Monoton
+	maxConns int
+	maxBatch int
+}
+
+// Option configures the optional behaviours of a Server
+type Option func(*Server)
+
+// WithMaxConns sets the max number of the concurrent connections, the default
+// is 1024. Zero or a negative value removes the limit.
+func WithMaxConns(n int) Option {
+	return func(s *Server) {
+		s.maxConns = n
+	}
+}
+
+// WithMaxBatch sets the max number of identifiers which can be generated with
+// one NEXTIDS command, the default is 1000
+func WithMaxBatch(n int) Option {
+	return func(s *Server) {
+		s.maxBatch = n
+	}
+}
+
+// New returns a Server which generates the identifiers with the given monoton
+func New(m monoton.Monoton, opts ...Option) *Server {
+	s := &Server{
+		monoton:  m,
+		maxConns: defaultMaxConns,
+		maxBatch: defaultMaxBatch,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Serve accepts the connections on the listener until the context is done,
+// then closes the listener and waits the connections to reply the commands
+// which are already read
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	srv := &netserve.Server{
+		Handle:   s.handle,
+		MaxConns: s.maxConns,
+		Reject: func(conn net.Conn) {
+			conn.Write([]byte(errMaxClients))
+		},
+	}
+	return srv.Serve(ctx, l)
+}
+
+func (s *Server) handle(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	w := &writer{w: bufio.NewWriter(conn)}
+	defer w.w.Flush()
+
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			var perr *ProtocolError
+			if errors.As(err, &perr) {
+				w.error(perr)
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		if quit := s.exec(w, args); quit {
+			return
+		}
+		// pipelined commands are replied together
+		if r.Buffered() == 0 {
+			if err := w.w.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// exec runs the command and writes its reply, it reports whether the
+// connection should be closed
+func (s *Server) exec(w *writer, args [][]byte) bool {
+	name := strings.ToUpper(string(args[0]))
+	switch name {
+	case "NEXTID":
+		if len(args) != 1 {
+			w.error(fmt.Errorf(errArgs, strings.ToLower(name)))
+			return false
+		}
+		id := s.monoton.NextBytes()
+		w.bulk(id[:])
+	case "NEXTIDS":
+		if len(args) != 2 {
+			w.error(fmt.Errorf(errArgs, strings.ToLower(name)))
+			return false
+		}
+		n, err := strconv.Atoi(string(args[1]))
+		if err != nil || n < 1 || n > s.maxBatch {
+			w.error(fmt.Errorf(errCount, s.maxBatch))
+			return false
+		}
+		ids := make([][16]byte, n)
+		s.monoton.NextBytesN(ids)
+		w.array(n)
+		for i := range ids {
+			w.bulk(ids[i][:])
+		}
+	case "INSPECT":
+		if len(args) != 2 {
+			w.error(fmt.Errorf(errArgs, strings.ToLower(name)))
+			return false
+		}
+		s.inspect(w, string(args[1]))
+	case "PING":
+		w.simple("PONG")
+	case "QUIT":
+		w.simple("OK")
+		return true
+	default:
+		w.error(fmt.Errorf(errUnknownCommand, args[0]))
+	}
+	return false
+}
+
+func (s *Server) inspect(w *writer, id string) {
+	i, err := s.monoton.Inspect(id)
+	if err != nil {
+		w.error(err)
+		return
+	}
+
+	w.array(6)
+	w.bulk([]byte("time"))
+	w.bulk([]byte(i.Time.Format(time.RFC3339Nano)))
+	w.bulk([]byte("counter"))
+	w.integer(i.Counter)
+	w.bulk([]byte("node"))
+	w.integer(i.Node)
+}
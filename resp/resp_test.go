@@ -0,0 +1,190 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package resp
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3"
+	"github.com/mustafaturan/monoton/v3/internal/servetest"
+	"github.com/mustafaturan/monoton/v3/mtimer"
+	"github.com/mustafaturan/monoton/v3/sequencer"
+)
+
+func TestServer(t *testing.T) {
+	addr, stop := startServer(t, WithMaxBatch(3))
+	defer stop()
+
+	tests := []struct {
+		request string
+		want    string
+	}{
+		{
+			"*1\r\n$6\r\nNEXTID\r\n",
+			"$16\r\n000000G80000000J\r\n",
+		},
+		{
+			"*2\r\n$7\r\nNEXTIDS\r\n$1\r\n2\r\n",
+			"*2\r\n$16\r\n000000G80001000J\r\n$16\r\n000000G80002000J\r\n",
+		},
+		{
+			"*2\r\n$7\r\ninspect\r\n$16\r\n000000G80001000J\r\n",
+			"*6\r\n$4\r\ntime\r\n$20\r\n1970-01-01T00:00:01Z\r\n$7\r\ncounter\r\n:1\r\n$4\r\nnode\r\n:19\r\n",
+		},
+		{"INSPECT 000000G8000_000J\r\n", "-ERR invalid char '_' at position 11\r\n"},
+		{"NEXTIDS 4\r\n", "-ERR n must be between 1 and 3\r\n"},
+		{"NEXTIDS\r\n", "-ERR wrong number of arguments for 'nextids' command\r\n"},
+		{"NEXTID 1\r\n", "-ERR wrong number of arguments for 'nextid' command\r\n"},
+		{"INSPECT\r\n", "-ERR wrong number of arguments for 'inspect' command\r\n"},
+		{"GET key\r\n", "-ERR unknown command \"GET\"\r\n"},
+		{
+			"*1\r\n$10\r\nX\r\n+OK\r\nYZ\r\n",
+			"-ERR unknown command \"X\\r\\n+OK\\r\\nYZ\"\r\n",
+		},
+		{"\r\nping\r\n", "+PONG\r\n"},
+	}
+
+	conn := dial(t, addr)
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for _, test := range tests {
+		if _, err := conn.Write([]byte(test.request)); err != nil {
+			t.Fatalf("Write(%q) returned error: %v", test.request, err)
+		}
+		if got := readN(t, r, len(test.want)); got != test.want {
+			t.Errorf("%q want: %q, got: %q", test.request, test.want, got)
+		}
+	}
+}
+
+func TestServerPipelining(t *testing.T) {
+	addr, stop := startServer(t)
+	defer stop()
+
+	conn := dial(t, addr)
+	defer conn.Close()
+
+	const commands = 100
+	request := strings.Repeat("*1\r\n$6\r\nNEXTID\r\n", commands) + "PING\r\n"
+	if _, err := conn.Write([]byte(request)); err != nil {
+		t.Fatalf("Write() returned error: %v", err)
+	}
+
+	r := bufio.NewReader(conn)
+	prev := ""
+	for i := 0; i < commands; i++ {
+		reply := readN(t, r, len("$16\r\n000000G80000000J\r\n"))
+		id := reply[5:21]
+		if !strings.HasPrefix(reply, "$16\r\n") || id <= prev {
+			t.Fatalf("reply %d want an id greater than %q, got: %q", i, prev, reply)
+		}
+		prev = id
+	}
+	if got := readN(t, r, len("+PONG\r\n")); got != "+PONG\r\n" {
+		t.Errorf("PING want: +PONG, got: %q", got)
+	}
+}
+
+func TestServerClose(t *testing.T) {
+	t.Run("closes the connection on QUIT", func(t *testing.T) {
+		addr, stop := startServer(t)
+		defer stop()
+
+		conn := dial(t, addr)
+		defer conn.Close()
+		conn.Write([]byte("QUIT\r\nPING\r\n"))
+		if got, _ := io.ReadAll(conn); string(got) != "+OK\r\n" {
+			t.Errorf("QUIT want: +OK and close, got: %q", got)
+		}
+	})
+
+	t.Run("closes the connection on protocol errors", func(t *testing.T) {
+		addr, stop := startServer(t)
+		defer stop()
+
+		conn := dial(t, addr)
+		defer conn.Close()
+		conn.Write([]byte("*1\r\n:1\r\nPING\r\n"))
+		want := "-ERR Protocol error: expected '$', got ':1'\r\n"
+		if got, _ := io.ReadAll(conn); string(got) != want {
+			t.Errorf("invalid request want: %q, got: %q", want, got)
+		}
+	})
+
+	t.Run("limits the number of connections", func(t *testing.T) {
+		addr, stop := startServer(t, WithMaxConns(1))
+		defer stop()
+
+		conn := dial(t, addr)
+		defer conn.Close()
+		conn.Write([]byte("PING\r\n"))
+		readN(t, bufio.NewReader(conn), len("+PONG\r\n"))
+
+		over := dial(t, addr)
+		defer over.Close()
+		want := "-ERR max number of clients reached\r\n"
+		if got, _ := io.ReadAll(over); string(got) != want {
+			t.Errorf("connection over the limit want: %q, got: %q", want, got)
+		}
+	})
+
+	t.Run("closes the idle connections on shutdown", func(t *testing.T) {
+		addr, stop := startServer(t)
+
+		conn := dial(t, addr)
+		defer conn.Close()
+		conn.Write([]byte("PING\r\n"))
+		r := bufio.NewReader(conn)
+		readN(t, r, len("+PONG\r\n"))
+
+		stop()
+		if got, err := io.ReadAll(r); err != nil || len(got) != 0 {
+			t.Errorf("idle connection want EOF, got: %q, %v", got, err)
+		}
+		if _, err := net.Dial("tcp", addr); err == nil {
+			t.Errorf("Dial() after shutdown want error, got nil")
+		}
+	})
+}
+
+// startServer serves a generator with a fake clock on a loopback listener and
+// returns the address with a function which stops the server
+func startServer(t *testing.T, opts ...Option) (string, func()) {
+	t.Helper()
+
+	clock := mtimer.NewFakeClock(time.Unix(1, 0))
+	s := sequencer.NewMillisecond(sequencer.WithClock(clock))
+	m, _ := monoton.New(s, 19, 0)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() returned error: %v", err)
+	}
+	return l.Addr().String(), servetest.Start(t, l, New(m, opts...).Serve)
+}
+
+func dial(t *testing.T, addr string) net.Conn {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("net.Dial() returned error: %v", err)
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+func readN(t *testing.T, r io.Reader, n int) string {
+	t.Helper()
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		t.Fatalf("ReadFull() returned error: %v, got: %q", err, b)
+	}
+	return string(b)
+}
@@ -0,0 +1,138 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package sequencer
+
+import (
+	"fmt"
+	"math/bits"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3/encoder"
+	"github.com/mustafaturan/monoton/v3/mtimer"
+)
+
+const (
+	totalByteSize = 16
+
+	errUnit     = "unit must be greater than 0 (given %s)"
+	errByteSize = "byte sizes of time(%d), counter(%d) and node(%d) must be " +
+		"between 1 and %d and their sum must be %d"
+)
+
+// InvalidUnitError is an error type with the given unit
+type InvalidUnitError struct {
+	Unit time.Duration
+}
+
+func (e *InvalidUnitError) Error() string {
+	return fmt.Sprintf(errUnit, e.Unit)
+}
+
+// ByteSizeError is an error type with the byte sizes of a layout
+type ByteSizeError struct {
+	TimeBytes     int
+	CounterBytes  int
+	NodeBytes     int
+	MaxByteSize   int
+	ByteSizeTotal int
+}
+
+func (e *ByteSizeError) Error() string {
+	return fmt.Sprintf(
+		errByteSize,
+		e.TimeBytes,
+		e.CounterBytes,
+		e.NodeBytes,
+		e.MaxByteSize,
+		e.ByteSizeTotal,
+	)
+}
+
+// Config is the time unit and the byte layout of a sequencer
+type Config struct {
+	// Unit is the duration of one time sequence value
+	Unit time.Duration
+	// TimeBytes is the byte size of the time sequence value
+	TimeBytes int
+	// CounterBytes is the byte size of the counter
+	CounterBytes int
+	// NodeBytes is the byte size of the node
+	NodeBytes int
+	// Encoding is the text encoding which the byte sizes are computed for,
+	// encoder.Base62 is used when it is nil
+	Encoding encoder.Encoding
+	// Clock is the source of the monotonic time, mtimer.Timer is used when it
+	// is nil
+	Clock mtimer.Clock
+}
+
+// NewCustom returns a sequencer with the given unit and byte layout, the max
+// values are computed from the byte sizes
+func NewCustom(c Config, opts ...Option) (*Sequence, error) {
+	if c.Unit <= 0 {
+		return nil, &InvalidUnitError{Unit: c.Unit}
+	}
+
+	// largest uint64 in the encoding occupies the max byte size
+	maxByteSize := c.encoding().ByteSize(1<<64 - 1)
+	if !validByteSize(c.TimeBytes, maxByteSize) ||
+		!validByteSize(c.CounterBytes, maxByteSize) ||
+		!validByteSize(c.NodeBytes, maxByteSize) ||
+		c.TimeBytes+c.CounterBytes+c.NodeBytes != totalByteSize {
+		return nil, &ByteSizeError{
+			TimeBytes:     c.TimeBytes,
+			CounterBytes:  c.CounterBytes,
+			NodeBytes:     c.NodeBytes,
+			MaxByteSize:   maxByteSize,
+			ByteSizeTotal: totalByteSize,
+		}
+	}
+	return newSequence(c, opts), nil
+}
+
+func newSequence(c Config, opts []Option) *Sequence {
+	clock := c.Clock
+	if clock == nil {
+		clock = mtimer.New()
+	}
+	base := c.encoding().Base()
+	s := &Sequence{
+		max:     maxValue(c.CounterBytes, base),
+		maxTime: maxValue(c.TimeBytes, base),
+		maxNode: maxValue(c.NodeBytes, base),
+		unit:    c.Unit,
+	}
+	WithClock(clock)(s)
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// encoding returns the configured encoding or encoder.Base62 when it is nil
+func (c Config) encoding() encoder.Encoding {
+	if c.Encoding == nil {
+		return encoder.Base62
+	}
+	return c.Encoding
+}
+
+func validByteSize(size, maxByteSize int) bool {
+	return size >= 1 && size <= maxByteSize
+}
+
+// maxValue returns the largest value of the base for the byte size which fits
+// into uint64
+func maxValue(byteSize int, base uint64) uint64 {
+	max := uint64(1)
+	for i := 0; i < byteSize; i++ {
+		hi, lo := bits.Mul64(max, base)
+		if hi != 0 {
+			return 1<<64 - 1
+		}
+		max = lo
+	}
+	return max - 1
+}
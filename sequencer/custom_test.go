@@ -0,0 +1,111 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package sequencer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3/encoder"
+	"github.com/mustafaturan/monoton/v3/mtimer"
+)
+
+func TestNewCustom(t *testing.T) {
+	tests := []struct {
+		config      Config
+		wantMax     uint64
+		wantMaxTime uint64
+		wantMaxNode uint64
+	}{
+		{
+			Config{Unit: time.Millisecond, TimeBytes: 8, CounterBytes: 6, NodeBytes: 2},
+			62*62*62*62*62*62 - 1,
+			62*62*62*62*62*62*62*62 - 1,
+			62*62 - 1,
+		},
+		{
+			Config{Unit: time.Hour, TimeBytes: 4, CounterBytes: 11, NodeBytes: 1},
+			1<<64 - 1,
+			62*62*62*62 - 1,
+			62 - 1,
+		},
+		{
+			Config{Unit: time.Millisecond, TimeBytes: 10, CounterBytes: 3, NodeBytes: 3, Encoding: encoder.Base32},
+			1<<15 - 1,
+			1<<50 - 1,
+			1<<15 - 1,
+		},
+		{
+			Config{Unit: time.Millisecond, TimeBytes: 10, CounterBytes: 3, NodeBytes: 3, Encoding: encoder.Base36},
+			36*36*36 - 1,
+			3656158440062975, // 36^10 - 1
+			36*36*36 - 1,
+		},
+	}
+
+	msg := "NewCustom(%+v) want: (%d, %d, %d), got: (%d, %d, %d)"
+	for _, test := range tests {
+		s, err := NewCustom(test.config)
+		if err != nil {
+			t.Fatalf("NewCustom(%+v) returned error: %v", test.config, err)
+		}
+		if s.Max() != test.wantMax ||
+			s.MaxTime() != test.wantMaxTime ||
+			s.MaxNode() != test.wantMaxNode ||
+			s.Unit() != test.config.Unit {
+			t.Errorf(
+				msg,
+				test.config,
+				test.wantMax, test.wantMaxTime, test.wantMaxNode,
+				s.Max(), s.MaxTime(), s.MaxNode(),
+			)
+		}
+	}
+
+	t.Run("uses the clock with the unit", func(t *testing.T) {
+		s, _ := NewCustom(Config{
+			Unit:         time.Microsecond,
+			TimeBytes:    10,
+			CounterBytes: 3,
+			NodeBytes:    3,
+			Clock:        mtimer.NewFakeClock(time.Unix(0, 5000)),
+		}, WithOverflowPolicy(OverflowBorrow))
+		if got, seq := s.Next(); got != 5 || seq != 0 {
+			t.Errorf("Next() want: 5, 0, got: %d, %d", got, seq)
+		}
+		if s.overflow != OverflowBorrow {
+			t.Errorf("NewCustom() should apply the options")
+		}
+	})
+
+	errorTests := []struct {
+		config Config
+		want   string
+	}{
+		{
+			Config{TimeBytes: 8, CounterBytes: 4, NodeBytes: 4},
+			"unit must be greater than 0 (given 0s)",
+		},
+		{
+			Config{Unit: time.Second, TimeBytes: 8, CounterBytes: 4, NodeBytes: 3},
+			"byte sizes of time(8), counter(4) and node(3) must be between 1 and 11 and their sum must be 16",
+		},
+		{
+			Config{Unit: time.Second, TimeBytes: 12, CounterBytes: 4, NodeBytes: 0},
+			"byte sizes of time(12), counter(4) and node(0) must be between 1 and 11 and their sum must be 16",
+		},
+		{
+			Config{Unit: time.Second, TimeBytes: 14, CounterBytes: 1, NodeBytes: 1, Encoding: encoder.Base32},
+			"byte sizes of time(14), counter(1) and node(1) must be between 1 and 13 and their sum must be 16",
+		},
+	}
+
+	for _, test := range errorTests {
+		s, err := NewCustom(test.config)
+		if s != nil || err == nil || err.Error() != test.want {
+			t.Errorf("NewCustom(%+v) want error: %s, got: %v", test.config, test.want, err)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package sequencer
+
+import (
+	"time"
+)
+
+// NewMicrosecond returns the preconfigured microsecond sequencer
+func NewMicrosecond(opts ...Option) *Sequence {
+	return newSequence(Config{
+		Unit:         time.Microsecond,
+		TimeBytes:    10,
+		CounterBytes: 3,
+		NodeBytes:    3,
+	}, opts)
+}
@@ -6,18 +6,14 @@ package sequencer
 
 import (
 	"time"
-
-	"github.com/mustafaturan/monoton/v3/mtimer"
 )
 
 // NewMillisecond returns the preconfigured millisecond sequencer
-func NewMillisecond() *Sequence {
-	millisecond := uint64(time.Millisecond)
-	timer := mtimer.New()
-	return &Sequence{
-		now:     func() uint64 { return timer.Now() / millisecond },
-		max:     62*62*62*62 - 1,
-		maxTime: 62*62*62*62*62*62*62*62 - 1,
-		maxNode: 62*62*62*62 - 1,
-	}
+func NewMillisecond(opts ...Option) *Sequence {
+	return newSequence(Config{
+		Unit:         time.Millisecond,
+		TimeBytes:    8,
+		CounterBytes: 4,
+		NodeBytes:    4,
+	}, opts)
 }
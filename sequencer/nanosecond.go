@@ -5,16 +5,15 @@
 package sequencer
 
 import (
-	"github.com/mustafaturan/monoton/v3/mtimer"
+	"time"
 )
 
 // NewNanosecond returns the preconfigured nanosecond sequencer
-func NewNanosecond() *Sequence {
-	timer := mtimer.New()
-	return &Sequence{
-		now:     timer.Now,
-		max:     62*62 - 1,
-		maxTime: uint64(1<<64 - 1),
-		maxNode: 62*62*62 - 1,
-	}
+func NewNanosecond(opts ...Option) *Sequence {
+	return newSequence(Config{
+		Unit:         time.Nanosecond,
+		TimeBytes:    11,
+		CounterBytes: 2,
+		NodeBytes:    3,
+	}, opts)
 }
@@ -6,18 +6,14 @@ package sequencer
 
 import (
 	"time"
-
-	"github.com/mustafaturan/monoton/v3/mtimer"
 )
 
 // NewSecond returns the preconfigured second sequencer
-func NewSecond() *Sequence {
-	second := uint64(time.Second)
-	timer := mtimer.New()
-	return &Sequence{
-		now:     func() uint64 { return timer.Now() / second },
-		max:     62*62*62*62*62*62 - 1,
-		maxTime: 62*62*62*62*62*62 - 1,
-		maxNode: 62*62*62*62 - 1,
-	}
+func NewSecond(opts ...Option) *Sequence {
+	return newSequence(Config{
+		Unit:         time.Second,
+		TimeBytes:    6,
+		CounterBytes: 6,
+		NodeBytes:    4,
+	}, opts)
 }
@@ -5,17 +5,97 @@
 package sequencer
 
 import (
+	"fmt"
+	"sync"
 	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/mustafaturan/monoton/v3/mtimer"
+)
+
+const (
+	// waitDivider splits the unit into smaller sleep intervals while waiting
+	// for the next time value
+	waitDivider = 10
+
+	// saveRetryDelay is the delay between the retries of the failed saves of
+	// the high-water mark on Next and Reserve
+	saveRetryDelay = 10 * time.Millisecond
+
+	errOverflow = "sequence exceeded the max value %d for time %d"
+)
+
+// OverflowPolicy defines what happens when the counter exceeds the maximum
+// sequence value for a time value
+type OverflowPolicy int
+
+const (
+	// OverflowWait waits until the time moves to the next value
+	OverflowWait OverflowPolicy = iota
+	// OverflowBorrow advances the time value by one without waiting for the
+	// clock, the clock catches up with the sequence later
+	OverflowBorrow
+	// OverflowFail makes TryNext return an *OverflowError, Next falls back to
+	// waiting since it can't return an error
+	OverflowFail
 )
 
+// OverflowError is an error type with the exhausted time value
+type OverflowError struct {
+	Time uint64
+	Max  uint64
+}
+
+func (e *OverflowError) Error() string {
+	return fmt.Sprintf(errOverflow, e.Max, e.Time)
+}
+
+// Option configures a Sequence
+type Option func(*Sequence)
+
+// WithOverflowPolicy sets the behaviour of the sequence on counter overflows,
+// the default is OverflowWait
+func WithOverflowPolicy(p OverflowPolicy) Option {
+	return func(s *Sequence) {
+		s.overflow = p
+	}
+}
+
+// WithClock sets the source of the monotonic time, it allows freezing and
+// advancing the time of the sequence with mtimer.FakeClock in tests
+func WithClock(c mtimer.Clock) Option {
+	return func(s *Sequence) {
+		unit := uint64(s.unit)
+		s.now = func() uint64 { return c.Now() / unit }
+	}
+}
+
 // Sequence is an implementation of sequencer
 type Sequence struct {
-	current uint64
+	// saved is the persisted high-water mark of the time values, it is the
+	// first field to keep the 64-bit atomic operations aligned
+	saved uint64
+	// state holds a *state which is replaced as a whole when the time changes,
+	// so the time and the counter of a tick are always read together
+	state    unsafe.Pointer
+	max      uint64
+	maxTime  uint64
+	maxNode  uint64
+	unit     time.Duration
+	overflow OverflowPolicy
+	now      func() uint64
+
+	mu    sync.Mutex
+	store StateStore
+	ahead uint64
+}
+
+// state is the counter of a single time value. Once it is published, only
+// its counter is modified and always atomically.
+type state struct {
+	counter uint64
 	time    uint64
-	max     uint64
-	maxTime uint64
-	maxNode uint64
-	now     func() uint64
 }
 
 // Max returns the maximum possible sequence value
@@ -33,17 +113,154 @@ func (s *Sequence) MaxNode() uint64 {
 	return s.maxNode
 }
 
+// Unit returns the duration of one time sequence value
+func (s *Sequence) Unit() time.Duration {
+	return s.unit
+}
+
+// Now returns the current time value of the sequence clock without generating
+// a sequence
+func (s *Sequence) Now() uint64 {
+	return s.now()
+}
+
 // Next returns the next sequence
 func (s *Sequence) Next() (uint64, uint64) {
+	t, seq, _, _ := s.reserve(1, false)
+	return t, seq
+}
+
+// TryNext returns the next sequence or an error when the counter is exhausted
+// for the current time and the policy is OverflowFail, or when the high-water
+// mark of the time can't be persisted
+func (s *Sequence) TryNext() (uint64, uint64, error) {
+	t, seq, _, err := s.reserve(1, true)
+	return t, seq, err
+}
+
+// Reserve reserves up to n contiguous sequences for a time value in one
+// operation, and returns the time, the first sequence and the reserved count.
+// The count is less than n when the counter of the time value is exhausted
+// before n.
+func (s *Sequence) Reserve(n uint64) (uint64, uint64, uint64) {
+	t, seq, count, _ := s.reserve(n, false)
+	return t, seq, count
+}
+
+// TryReserve reserves up to n contiguous sequences like Reserve, or returns an
+// error when the counter is exhausted for the current time and the policy is
+// OverflowFail, or when the high-water mark of the time can't be persisted
+func (s *Sequence) TryReserve(n uint64) (uint64, uint64, uint64, error) {
+	return s.reserve(n, true)
+}
+
+func (s *Sequence) reserve(n uint64, try bool) (uint64, uint64, uint64, error) {
+	if n == 0 {
+		n = 1
+	}
+	policy := s.overflow
+	if policy == OverflowFail && !try {
+		policy = OverflowWait
+	}
+
 	now := s.now()
-	time := atomic.LoadUint64(&s.time)
-	var current uint64
-	if time < now {
-		time = now
-		atomic.StoreUint64(&s.time, time)
-		atomic.StoreUint64(&s.current, 0)
-	} else {
-		current = atomic.AddUint64(&s.current, 1)
-	}
-	return time, current
+	for {
+		current := s.load()
+		if current.time < now {
+			// Only one of the concurrent calls can replace the state of the
+			// tick, the others retry and increment the counter of the new state
+			count, err := s.advance(current, now, n, try)
+			if err != nil {
+				return 0, 0, 0, err
+			}
+			if count > 0 {
+				return now, 0, count, nil
+			}
+			continue
+		}
+
+		// The counter can only wrap around when the max is close to the
+		// largest uint64
+		last := atomic.AddUint64(&current.counter, n)
+		first := last - n + 1
+		if last >= n && first <= s.max {
+			if last > s.max {
+				last = s.max
+			}
+			return current.time, first, last - first + 1, nil
+		}
+
+		switch policy {
+		case OverflowBorrow:
+			count, err := s.advance(current, current.time+1, n, try)
+			if err != nil {
+				return 0, 0, 0, err
+			}
+			if count > 0 {
+				return current.time + 1, 0, count, nil
+			}
+		case OverflowFail:
+			return 0, 0, 0, &OverflowError{Time: current.time, Max: s.max}
+		default:
+			now = s.wait(current.time)
+		}
+	}
+}
+
+// advance replaces the current state with a new state of the given time which
+// has up to n reserved sequences after persisting the time, and returns the
+// reserved count or 0 when another call replaced the state. The persistence
+// errors are only returned on try, otherwise the save is retried until it
+// succeeds since the time values past the persisted mark can't be issued.
+func (s *Sequence) advance(
+	current *state,
+	t, n uint64,
+	try bool,
+) (uint64, error) {
+	for {
+		err := s.persist(t)
+		if err == nil {
+			break
+		}
+		if try {
+			return 0, err
+		}
+		time.Sleep(saveRetryDelay)
+	}
+
+	count := n
+	if s.max < n-1 {
+		count = s.max + 1
+	}
+	if !s.swap(current, &state{time: t, counter: count - 1}) {
+		return 0, nil
+	}
+	return count, nil
+}
+
+// wait blocks until the clock passes the given time value
+func (s *Sequence) wait(t uint64) uint64 {
+	for {
+		if now := s.now(); now > t {
+			return now
+		}
+		time.Sleep(s.unit / waitDivider)
+	}
+}
+
+func (s *Sequence) load() *state {
+	p := atomic.LoadPointer(&s.state)
+	if p == nil {
+		atomic.CompareAndSwapPointer(&s.state, nil, unsafe.Pointer(&state{}))
+		p = atomic.LoadPointer(&s.state)
+	}
+	return (*state)(p)
+}
+
+func (s *Sequence) swap(old, new *state) bool {
+	return atomic.CompareAndSwapPointer(
+		&s.state,
+		unsafe.Pointer(old),
+		unsafe.Pointer(new),
+	)
 }
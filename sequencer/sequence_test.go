@@ -5,6 +5,9 @@
 package sequencer
 
 import (
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -38,6 +41,24 @@ func TestMaxNode_Sequence(t *testing.T) {
 	}
 }
 
+func TestUnit_Sequence(t *testing.T) {
+	want := time.Microsecond
+	s := &Sequence{unit: want}
+
+	if got := s.Unit(); got != want {
+		t.Errorf("Unit() want: %s, got: %s", want, got)
+	}
+}
+
+func TestNow_Sequence(t *testing.T) {
+	want := uint64(42)
+	s := &Sequence{now: func() uint64 { return want }}
+
+	if got := s.Now(); got != want {
+		t.Errorf("Now() want: %d, got: %d", want, got)
+	}
+}
+
 func TestNext_Sequence(t *testing.T) {
 	timer := mtimer.New()
 	tests := []struct {
@@ -52,7 +73,7 @@ func TestNext_Sequence(t *testing.T) {
 		test := test
 		t.Run("resets counter correctly when time changes", func(t *testing.T) {
 			t.Parallel()
-			s := &Sequence{now: test.now}
+			s := &Sequence{max: 1<<64 - 1, now: test.now}
 			s.Next()
 			s.Next()
 			if _, got := s.Next(); got != test.want {
@@ -61,3 +82,178 @@ func TestNext_Sequence(t *testing.T) {
 		})
 	}
 }
+
+func TestNext_Sequence_Overflow(t *testing.T) {
+	newSequence := func(policy OverflowPolicy) (*Sequence, *uint64) {
+		clock := uint64(5)
+		s := &Sequence{
+			max:      1,
+			unit:     time.Millisecond,
+			overflow: policy,
+			now:      func() uint64 { return atomic.LoadUint64(&clock) },
+		}
+		return s, &clock
+	}
+	tick := func(clock *uint64) {
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddUint64(clock, 1)
+	}
+
+	tests := []struct {
+		policy OverflowPolicy
+		want   [][2]uint64
+	}{
+		{OverflowWait, [][2]uint64{{5, 0}, {5, 1}, {6, 0}, {6, 1}}},
+		{OverflowBorrow, [][2]uint64{{5, 0}, {5, 1}, {6, 0}, {6, 1}, {7, 0}}},
+		{OverflowFail, [][2]uint64{{5, 0}, {5, 1}, {6, 0}, {6, 1}}},
+	}
+
+	for _, test := range tests {
+		s, clock := newSequence(test.policy)
+		if test.policy != OverflowBorrow {
+			go tick(clock)
+		}
+		for i, want := range test.want {
+			var got [2]uint64
+			got[0], got[1] = s.Next()
+			if got != want {
+				t.Errorf("Next() with policy %d call %d want: %v, got: %v", test.policy, i, want, got)
+			}
+		}
+	}
+
+	t.Run("TryNext errors on overflow with fail policy", func(t *testing.T) {
+		s, _ := newSequence(OverflowFail)
+		s.Next()
+		s.Next()
+		want := "sequence exceeded the max value 1 for time 5"
+		if _, _, err := s.TryNext(); err == nil || err.Error() != want {
+			t.Errorf("TryNext() want error: %s, got: %v", want, err)
+		}
+	})
+
+	t.Run("TryNext applies the other policies", func(t *testing.T) {
+		s, _ := newSequence(OverflowBorrow)
+		s.Next()
+		s.Next()
+		if gotTime, got, err := s.TryNext(); err != nil || gotTime != 6 || got != 0 {
+			t.Errorf("TryNext() want: 6, 0, <nil>, got: %d, %d, %v", gotTime, got, err)
+		}
+	})
+}
+
+func TestReserve_Sequence(t *testing.T) {
+	s := &Sequence{
+		max:      9,
+		overflow: OverflowBorrow,
+		now:      func() uint64 { return 5 },
+	}
+
+	tests := []struct {
+		n    uint64
+		want [3]uint64
+	}{
+		{4, [3]uint64{5, 0, 4}},
+		{4, [3]uint64{5, 4, 4}},
+		{4, [3]uint64{5, 8, 2}},
+		{4, [3]uint64{6, 0, 4}},
+		{0, [3]uint64{6, 4, 1}},
+		{20, [3]uint64{6, 5, 5}},
+		{20, [3]uint64{7, 0, 10}},
+	}
+
+	for i, test := range tests {
+		var got [3]uint64
+		got[0], got[1], got[2] = s.Reserve(test.n)
+		if got != test.want {
+			t.Errorf("Reserve(%d) call %d want: %v, got: %v", test.n, i, test.want, got)
+		}
+	}
+
+	t.Run("continues with the next sequence", func(t *testing.T) {
+		if gotTime, got := s.Next(); gotTime != 8 || got != 0 {
+			t.Errorf("Next() want: 8, 0, got: %d, %d", gotTime, got)
+		}
+	})
+}
+
+func TestTryReserve_Sequence(t *testing.T) {
+	s := &Sequence{
+		max:      9,
+		overflow: OverflowFail,
+		now:      func() uint64 { return 5 },
+	}
+
+	tests := []struct {
+		n    uint64
+		want [3]uint64
+	}{
+		{4, [3]uint64{5, 0, 4}},
+		{8, [3]uint64{5, 4, 6}},
+	}
+
+	for i, test := range tests {
+		var got [3]uint64
+		var err error
+		got[0], got[1], got[2], err = s.TryReserve(test.n)
+		if got != test.want || err != nil {
+			t.Errorf("TryReserve(%d) call %d want: %v, <nil>, got: %v, %v", test.n, i, test.want, got, err)
+		}
+	}
+
+	t.Run("errors on overflow with fail policy", func(t *testing.T) {
+		want := "sequence exceeded the max value 9 for time 5"
+		if _, _, _, err := s.TryReserve(1); err == nil || err.Error() != want {
+			t.Errorf("TryReserve() want error: %s, got: %v", want, err)
+		}
+	})
+}
+
+func TestNext_Sequence_Concurrent(t *testing.T) {
+	// the time changes in every few calls to make the goroutines cross the
+	// tick boundaries as often as possible
+	var calls uint64
+	s := &Sequence{
+		max: 1<<64 - 1,
+		now: func() uint64 { return atomic.AddUint64(&calls, 1) / 8 },
+	}
+
+	total := 1 << 21
+	if testing.Short() {
+		total = 1 << 16
+	}
+	workers := runtime.GOMAXPROCS(0) * 2
+	results := make([][][2]uint64, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			vals := make([][2]uint64, total/workers)
+			for i := range vals {
+				vals[i][0], vals[i][1] = s.Next()
+			}
+			results[w] = vals
+		}(w)
+	}
+	wg.Wait()
+
+	seen := make(map[[2]uint64]struct{}, total)
+	for w, vals := range results {
+		for i, v := range vals {
+			if _, ok := seen[v]; ok {
+				t.Fatalf("Next() generated duplicate value: %v", v)
+			}
+			seen[v] = struct{}{}
+
+			if i == 0 {
+				continue
+			}
+			prev := vals[i-1]
+			if v[0] < prev[0] || (v[0] == prev[0] && v[1] <= prev[1]) {
+				t.Fatalf("Next() is not incremental for worker %d: %v, %v", w, prev, v)
+			}
+		}
+	}
+}
@@ -23,17 +23,45 @@
 // may not accurately reflect the actual time that passed between t and u which
 // will result with incorrect sequences.
 //
+// # Time - Clocks
+//
+// The sequencers read the time from mtimer.Timer by default. Any other
+// mtimer.Clock can be given with the WithClock option, for example the
+// mtimer.FakeClock freezes and advances the time to assert exact sequences in
+// tests.
+//
+// # Time - Restarts
+//
+// The mtimer.Timer anchors to the system time on start, so a restarted process
+// can reissue the time values of the previous process if the system clock
+// steps backwards in between. Persist restores the high-water mark of the
+// issued time values from a StateStore like FileStore and keeps it up to date:
+//
+//	s := sequencer.NewMillisecond(
+//		sequencer.WithOverflowPolicy(sequencer.OverflowFail),
+//	)
+//	err := s.Persist(sequencer.NewFileStore("/var/lib/app/monoton"), time.Second)
+//
+// # Time - Processes
+//
+// On Linux, NewShared keeps the time and the counter of a sequence in a memory
+// mapped file under SharedDir, so the processes of a host can share one node.
+// The state is only modified with atomic operations, so a crashed process
+// never leaves a partial state behind:
+//
+//	s, err := sequencer.NewShared("/dev/shm/monoton", sequencer.NewMillisecond())
+//
 // # Byte Sizes
 //
 // The total byte size is fixed to 16 bytes for any sequencer. And at least one
-// byte is reserved to nodes. The package comes with three pre-configured
+// byte is reserved to nodes. The package comes with four pre-configured
 // sequencers and Sequencer interface to allow new sequencers.
 //
 // # Byte Sizes - Defaults
 //
 // The package comes with pre-configured byte sizes for the Nanosecond,
-// Millisecond and Second sequencers. And it does not allow you to adjust
-// current sizes unless you create another sequencer.
+// Microsecond, Millisecond and Second sequencers. And it does not allow you to
+// adjust current sizes unless you create another sequencer.
 // The defaults are adjusted the time and sequence byte sizes depending on
 // general needs and to increase compatibility between projects.
 //
@@ -41,8 +69,35 @@
 //
 //	Second:      16 B =>  6 B (seconds)      + 6 B (counter) + 4 B (node)
 //	Millisecond: 16 B =>  8 B (milliseconds) + 4 B (counter) + 4 B (node)
+//	Microsecond: 16 B => 10 B (microseconds) + 3 B (counter) + 3 B (node)
 //	Nanosecond:  16 B => 11 B (nanoseconds)  + 2 B (counter) + 3 B (node)
 //
+// # Byte Sizes - Custom
+//
+// The NewCustom constructor creates a sequencer with any time unit and byte
+// layout. The max values are computed from the byte sizes of the Config, so
+// for example the node bytes can be traded for the counter bytes:
+//
+//	s, err := sequencer.NewCustom(sequencer.Config{
+//		Unit:         time.Millisecond,
+//		TimeBytes:    8,
+//		CounterBytes: 6,
+//		NodeBytes:    2,
+//	})
+//
+// The byte sizes are computed for Base62 unless the Encoding of the Config is
+// set to one of the other encodings of the monoton/encoder package.
+//
+// # Counter Overflows
+//
+// The counter of a time value is limited by the Max value of the sequencer.
+// When the counter is exhausted, the Sequence applies one of the overflow
+// policies configured with the WithOverflowPolicy option:
+//
+//	OverflowWait:   waits until the time moves to the next value (default)
+//	OverflowBorrow: advances the time value without waiting for the clock
+//	OverflowFail:   TryNext returns an *OverflowError
+//
 // # Byte Sizes - Consequences
 //
 // Although a strict byte size is limiting the space for nodes and sequences,
@@ -50,6 +105,10 @@
 // it could be necessary to provide a strategy to upgrade byte size to 32 B.
 package sequencer
 
+import (
+	"time"
+)
+
 // Sequencer is a generic behavior for the sequence generators
 type Sequencer interface {
 	// Max returns the maximum possible sequence value for a given time
@@ -58,6 +117,35 @@ type Sequencer interface {
 	MaxTime() uint64
 	// MaxNode returns the maximum possible node value
 	MaxNode() uint64
-	// Now returns the current monotonic time
+	// Unit returns the duration of one time sequence value
+	Unit() time.Duration
+	// Next returns the current monotonic time and the sequence for the time
 	Next() (uint64, uint64)
 }
+
+// TrySequencer is a Sequencer which can report the errors while generating
+// sequences instead of applying a fallback
+type TrySequencer interface {
+	Sequencer
+	// TryNext returns the current monotonic time and the sequence for the
+	// time or an error when a sequence can't be generated
+	TryNext() (uint64, uint64, error)
+}
+
+// Reserver is a Sequencer which can reserve a contiguous range of sequences for
+// a time value in one operation
+type Reserver interface {
+	Sequencer
+	// Reserve reserves up to n contiguous sequences for a time value, and
+	// returns the time, the first sequence and the reserved count
+	Reserve(n uint64) (uint64, uint64, uint64)
+}
+
+// TryReserver is a Reserver which can report the errors while reserving the
+// sequences instead of applying a fallback
+type TryReserver interface {
+	Reserver
+	// TryReserve reserves up to n contiguous sequences for a time value like
+	// Reserve, or returns an error when no sequence can be reserved
+	TryReserve(n uint64) (uint64, uint64, uint64, error)
+}
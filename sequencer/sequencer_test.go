@@ -9,6 +9,8 @@ import (
 	"reflect"
 	"testing"
 	"time"
+
+	"github.com/mustafaturan/monoton/v3/mtimer"
 )
 
 func TestMaxTime(t *testing.T) {
@@ -18,6 +20,7 @@ func TestMaxTime(t *testing.T) {
 	}{
 		{uint64(math.Pow(62, 6) - 1), NewSecond()},
 		{uint64(math.Pow(62, 8) - 1), NewMillisecond()},
+		{62*62*62*62*62*62*62*62*62*62 - 1, NewMicrosecond()},
 		{uint64(1<<64 - 1), NewNanosecond()},
 	}
 
@@ -42,6 +45,7 @@ func TestMax(t *testing.T) {
 	}{
 		{uint64(math.Pow(62, 6) - 1), NewSecond()},
 		{uint64(math.Pow(62, 4) - 1), NewMillisecond()},
+		{uint64(math.Pow(62, 3) - 1), NewMicrosecond()},
 		{uint64(math.Pow(62, 2) - 1), NewNanosecond()},
 	}
 
@@ -60,14 +64,80 @@ func TestMax(t *testing.T) {
 	}
 }
 
+func TestUnit(t *testing.T) {
+	tests := []struct {
+		wantUnit time.Duration
+		seq      Sequencer
+	}{
+		{time.Second, NewSecond()},
+		{time.Millisecond, NewMillisecond()},
+		{time.Microsecond, NewMicrosecond()},
+		{time.Nanosecond, NewNanosecond()},
+	}
+
+	for _, test := range tests {
+		gotUnit := test.seq.Unit()
+		if test.wantUnit != gotUnit {
+			t.Errorf(
+				"%s.Unit(), want: %s, got: %s",
+				reflect.TypeOf(test.seq).String(),
+				test.wantUnit,
+				gotUnit,
+			)
+		}
+	}
+}
+
+func TestWithOverflowPolicy(t *testing.T) {
+	tests := []struct {
+		want OverflowPolicy
+		seq  *Sequence
+	}{
+		{OverflowWait, NewMillisecond()},
+		{OverflowBorrow, NewSecond(WithOverflowPolicy(OverflowBorrow))},
+		{OverflowFail, NewNanosecond(WithOverflowPolicy(OverflowFail))},
+	}
+
+	for _, test := range tests {
+		if got := test.seq.overflow; got != test.want {
+			t.Errorf("WithOverflowPolicy() want: %d, got: %d", test.want, got)
+		}
+	}
+}
+
+func TestWithClock(t *testing.T) {
+	c := mtimer.NewFakeClock(time.Unix(10, 0))
+	tests := []struct {
+		want [2]uint64
+		seq  *Sequence
+	}{
+		{[2]uint64{10, 0}, NewSecond(WithClock(c))},
+		{[2]uint64{10000, 0}, NewMillisecond(WithClock(c))},
+		{[2]uint64{10000000, 0}, NewMicrosecond(WithClock(c))},
+		{[2]uint64{10000000000, 0}, NewNanosecond(WithClock(c))},
+	}
+
+	for _, test := range tests {
+		var got [2]uint64
+		got[0], got[1] = test.seq.Next()
+		if got != test.want {
+			t.Errorf("Next() with clock want: %v, got: %v", test.want, got)
+		}
+	}
+
+	c.Advance(time.Second)
+	if got := tests[0].seq.Now(); got != 11 {
+		t.Errorf("Now() after Advance() want: 11, got: %d", got)
+	}
+}
+
 func TestNext(t *testing.T) {
 	sameMomentTests := []struct {
 		sequencer Sequencer
 	}{
 		{NewSecond()},
 		{NewMillisecond()},
-		// NOTE: For Nanosecond type it is possible to use the known time
-		// freezing techniques to test
+		{NewNanosecond(WithClock(mtimer.NewFakeClock(time.Now())))},
 	}
 
 	for _, test := range sameMomentTests {
@@ -103,6 +173,7 @@ func TestNext(t *testing.T) {
 	}{
 		{NewSecond(), time.Second},
 		{NewMillisecond(), time.Millisecond},
+		{NewMicrosecond(), time.Microsecond},
 		{NewNanosecond(), time.Nanosecond},
 	}
 
@@ -0,0 +1,385 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package sequencer
+
+import (
+	"fmt"
+	"math/bits"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+const (
+	// SharedDir is the shared memory directory of Linux for the state files
+	SharedDir = "/dev/shm"
+
+	// SharedVersion is the version of the shared state layout
+	SharedVersion = 1
+
+	// sharedMagic marks the initialized state files, it is written after the
+	// rest of the header
+	sharedMagic = 0x6e6f746f6e6f6d // "monoton" in little endian
+
+	// sharedSize is the byte size of the state file, every field is a uint64
+	// in the native byte order
+	sharedSize = 8 * 8
+
+	// sharedMinTagBits is the min bit count of the time tags of the counter
+	sharedMinTagBits = 16
+
+	// sharedRetries is the count of attempts to open a state file whose
+	// initializer crashed before completing it
+	sharedRetries = 10
+
+	errSharedVersion = "shared state %s has version %d, want %d"
+	errSharedLayout  = "shared state %s is in use with a different layout"
+	errSharedCounter = "counter max %d is too large for a shared state"
+	errSharedClosed  = "shared state %s is closed"
+)
+
+// offsets of the header and the state fields in the state file
+const (
+	offsetMagic = 8 * iota
+	offsetVersion
+	offsetUnit
+	offsetMax
+	offsetMaxTime
+	offsetMaxNode
+	offsetTime
+	offsetCounter
+)
+
+// SharedVersionError is an error type with the version of a state file which
+// is in use by other processes
+type SharedVersionError struct {
+	Path    string
+	Version uint64
+	Want    uint64
+}
+
+func (e *SharedVersionError) Error() string {
+	return fmt.Sprintf(errSharedVersion, e.Path, e.Version, e.Want)
+}
+
+// SharedLayoutError is an error type with the path of a state file which is in
+// use by other processes with a different unit or byte layout
+type SharedLayoutError struct {
+	Path string
+}
+
+func (e *SharedLayoutError) Error() string {
+	return fmt.Sprintf(errSharedLayout, e.Path)
+}
+
+// SharedCounterError is an error type with the max counter value which leaves
+// too few bits to tag the counter with its time value
+type SharedCounterError struct {
+	Max uint64
+}
+
+func (e *SharedCounterError) Error() string {
+	return fmt.Sprintf(errSharedCounter, e.Max)
+}
+
+// SharedClosedError is an error type with the path of a state file which is
+// unmapped by Close
+type SharedClosedError struct {
+	Path string
+}
+
+func (e *SharedClosedError) Error() string {
+	return fmt.Sprintf(errSharedClosed, e.Path)
+}
+
+// Shared is a sequencer whose time and counter live in a memory mapped file,
+// so the processes of a host can generate the sequences of one node together.
+//
+// The time value and the counter are two words which are only modified with
+// atomic compare and swap operations. The counter is tagged with the lower
+// bits of its time value, so a counter of a previous time value is detected
+// and restarted from zero by any process. Since there is no lock on the state,
+// a process crash never leaves a partially updated state behind.
+type Shared struct {
+	seq  *Sequence
+	path string
+	file *os.File
+	mem  []byte
+
+	time        *uint64
+	counter     *uint64
+	counterBits uint
+	tagMask     uint64
+
+	// mu guards the mapping, the generation calls hold it for reading and
+	// Close for writing
+	mu       sync.RWMutex
+	closed   bool
+	closeErr error
+}
+
+// NewShared maps the state file at the path and returns a sequencer with the
+// layout, the clock and the overflow policy of the given sequence, the path is
+// usually under SharedDir.
+//
+// The file is created when it doesn't exist. When no other process uses the
+// file and it has a different version or layout, it is treated as stale and
+// reinitialized, otherwise a *SharedVersionError or a *SharedLayoutError is
+// returned. Each process holds a shared lock on the file until Close, and
+// the lock is released by the kernel when the process crashes.
+//
+// The time values never go backwards while the file exists, even across the
+// restarts of the processes.
+func NewShared(path string, seq *Sequence) (*Shared, error) {
+	counterBits := uint(64)
+	if seq.max < 1<<64-1 {
+		counterBits = uint(bits.Len64(seq.max + 1))
+	}
+	if counterBits > 64-sharedMinTagBits {
+		return nil, &SharedCounterError{Max: seq.max}
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	s := &Shared{
+		seq:         seq,
+		path:        path,
+		file:        file,
+		counterBits: counterBits,
+		tagMask:     1<<(64-counterBits) - 1,
+	}
+	if err := s.open(); err != nil {
+		if s.mem != nil {
+			unmapShared(s.mem)
+		}
+		file.Close()
+		return nil, err
+	}
+	s.time = s.word(offsetTime)
+	s.counter = s.word(offsetCounter)
+	return s, nil
+}
+
+// open initializes the file when no other process uses it, and maps it after
+// taking the shared lock
+func (s *Shared) open() error {
+	for attempt := 1; ; attempt++ {
+		exclusive, err := tryLockExclusive(s.file)
+		if err != nil {
+			return err
+		}
+		if exclusive {
+			if err := s.init(); err != nil {
+				return err
+			}
+		}
+		// Converts the exclusive lock or waits for the initializer
+		if err := lockShared(s.file); err != nil {
+			return err
+		}
+
+		info, err := s.file.Stat()
+		if err != nil {
+			return err
+		}
+		if info.Size() >= sharedSize {
+			if s.mem, err = mapShared(s.file, sharedSize); err != nil {
+				return err
+			}
+			if s.load(offsetMagic) == sharedMagic {
+				return s.validate()
+			}
+			if err := unmapShared(s.mem); err != nil {
+				return err
+			}
+			s.mem = nil
+		}
+
+		// The initializer crashed before completing the file, the next
+		// attempt initializes it unless another process does
+		if err := unlockShared(s.file); err != nil {
+			return err
+		}
+		if attempt == sharedRetries {
+			return &SharedLayoutError{Path: s.path}
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// init writes the header of a stale or new file while holding the exclusive
+// lock, a file with the same version and layout keeps its state
+func (s *Shared) init() error {
+	info, err := s.file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() != sharedSize {
+		if err := s.file.Truncate(sharedSize); err != nil {
+			return err
+		}
+	}
+
+	mem, err := mapShared(s.file, sharedSize)
+	if err != nil {
+		return err
+	}
+	s.mem = mem
+	if s.load(offsetMagic) != sharedMagic || s.validate() != nil {
+		s.store(offsetMagic, 0)
+		s.store(offsetVersion, SharedVersion)
+		s.store(offsetUnit, uint64(s.seq.unit))
+		s.store(offsetMax, s.seq.max)
+		s.store(offsetMaxTime, s.seq.maxTime)
+		s.store(offsetMaxNode, s.seq.maxNode)
+		s.store(offsetTime, 0)
+		s.store(offsetCounter, 0)
+		s.store(offsetMagic, sharedMagic)
+	}
+	s.mem = nil
+	return unmapShared(mem)
+}
+
+// validate compares the header of the file with the sequence
+func (s *Shared) validate() error {
+	if v := s.load(offsetVersion); v != SharedVersion {
+		return &SharedVersionError{Path: s.path, Version: v, Want: SharedVersion}
+	}
+	if s.load(offsetUnit) != uint64(s.seq.unit) ||
+		s.load(offsetMax) != s.seq.max ||
+		s.load(offsetMaxTime) != s.seq.maxTime ||
+		s.load(offsetMaxNode) != s.seq.maxNode {
+		return &SharedLayoutError{Path: s.path}
+	}
+	return nil
+}
+
+// Max returns the maximum possible sequence value
+func (s *Shared) Max() uint64 {
+	return s.seq.max
+}
+
+// MaxTime returns the maximum possible time sequence value
+func (s *Shared) MaxTime() uint64 {
+	return s.seq.maxTime
+}
+
+// MaxNode returns the maximum possible node value
+func (s *Shared) MaxNode() uint64 {
+	return s.seq.maxNode
+}
+
+// Unit returns the duration of one time sequence value
+func (s *Shared) Unit() time.Duration {
+	return s.seq.unit
+}
+
+// Now returns the current time value of the sequence clock without generating
+// a sequence
+func (s *Shared) Now() uint64 {
+	return s.seq.now()
+}
+
+// Next returns the next sequence, it panics with a *SharedClosedError after
+// Close
+func (s *Shared) Next() (uint64, uint64) {
+	t, seq, err := s.next(false)
+	if err != nil {
+		panic(err)
+	}
+	return t, seq
+}
+
+// TryNext returns the next sequence or an error when the counter is exhausted
+// for the current time and the policy is OverflowFail, or a *SharedClosedError
+// after Close
+func (s *Shared) TryNext() (uint64, uint64, error) {
+	return s.next(true)
+}
+
+// Close unmaps the state file and releases its lock after the concurrent calls
+// return, the file is kept to preserve the time values for the next processes
+func (s *Shared) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return s.closeErr
+	}
+	s.closed = true
+	s.closeErr = unmapShared(s.mem)
+	if err := s.file.Close(); s.closeErr == nil {
+		s.closeErr = err
+	}
+	return s.closeErr
+}
+
+func (s *Shared) next(try bool) (uint64, uint64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return 0, 0, &SharedClosedError{Path: s.path}
+	}
+
+	policy := s.seq.overflow
+	if policy == OverflowFail && !try {
+		policy = OverflowWait
+	}
+
+	for {
+		// The time values of the other processes are used when their clocks
+		// are ahead, so the time never goes backwards
+		t := atomic.LoadUint64(s.time)
+		if now := s.seq.now(); t < now {
+			atomic.CompareAndSwapUint64(s.time, t, now)
+			continue
+		}
+
+		// The time is loaded again to make sure the counter doesn't belong to
+		// a later time value before restarting it
+		c := atomic.LoadUint64(s.counter)
+		if atomic.LoadUint64(s.time) != t {
+			continue
+		}
+		next := uint64(0)
+		if c>>s.counterBits == t&s.tagMask {
+			next = c & (1<<s.counterBits - 1)
+		}
+
+		if next <= s.seq.max {
+			tagged := (t&s.tagMask)<<s.counterBits | (next + 1)
+			if atomic.CompareAndSwapUint64(s.counter, c, tagged) {
+				return t, next, nil
+			}
+			continue
+		}
+
+		switch policy {
+		case OverflowBorrow:
+			atomic.CompareAndSwapUint64(s.time, t, t+1)
+		case OverflowFail:
+			return 0, 0, &OverflowError{Time: t, Max: s.seq.max}
+		default:
+			s.seq.wait(t)
+		}
+	}
+}
+
+// word returns the pointer of the uint64 field at the offset of the mapping
+func (s *Shared) word(offset int) *uint64 {
+	return (*uint64)(unsafe.Pointer(&s.mem[offset]))
+}
+
+func (s *Shared) load(offset int) uint64 {
+	return atomic.LoadUint64(s.word(offset))
+}
+
+func (s *Shared) store(offset int, v uint64) {
+	atomic.StoreUint64(s.word(offset), v)
+}
@@ -0,0 +1,47 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+//go:build linux
+// +build linux
+
+package sequencer
+
+import (
+	"os"
+	"syscall"
+)
+
+// tryLockExclusive locks the file exclusively without blocking, it reports
+// false when another process holds a lock on the file
+func tryLockExclusive(file *os.File) (bool, error) {
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err == syscall.EWOULDBLOCK {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// lockShared takes or converts to the shared lock of the file, it blocks while
+// another process holds the exclusive lock
+func lockShared(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_SH)
+}
+
+func unlockShared(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
+
+func mapShared(file *os.File, size int) ([]byte, error) {
+	return syscall.Mmap(
+		int(file.Fd()),
+		0,
+		size,
+		syscall.PROT_READ|syscall.PROT_WRITE,
+		syscall.MAP_SHARED,
+	)
+}
+
+func unmapShared(mem []byte) error {
+	return syscall.Munmap(mem)
+}
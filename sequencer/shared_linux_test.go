@@ -0,0 +1,386 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+//go:build linux
+// +build linux
+
+package sequencer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3/mtimer"
+)
+
+const (
+	envSharedPath  = "MONOTON_SHARED_PATH"
+	envSharedCount = "MONOTON_SHARED_COUNT"
+	envSharedCrash = "MONOTON_SHARED_CRASH"
+)
+
+// TestSharedHelperProcess is not a real test, it generates sequences from the
+// state file in a child process of the multi-process tests and prints them
+func TestSharedHelperProcess(t *testing.T) {
+	path := os.Getenv(envSharedPath)
+	if path == "" {
+		return
+	}
+	count, err := strconv.Atoi(os.Getenv(envSharedCount))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	s, err := NewShared(path, NewMillisecond())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	out := bufio.NewWriter(os.Stdout)
+	for i := 0; i < count; i++ {
+		t, seq := s.Next()
+		fmt.Fprintf(out, "%d %d\n", t, seq)
+	}
+	out.Flush()
+
+	// Exits without closing the sequencer like a crashed process
+	if os.Getenv(envSharedCrash) != "" {
+		os.Exit(3)
+	}
+	s.Close()
+	os.Exit(0)
+}
+
+func runSharedHelper(path string, count int, crash bool) *exec.Cmd {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestSharedHelperProcess$")
+	cmd.Env = append(
+		os.Environ(),
+		envSharedPath+"="+path,
+		envSharedCount+"="+strconv.Itoa(count),
+	)
+	if crash {
+		cmd.Env = append(cmd.Env, envSharedCrash+"=1")
+	}
+	return cmd
+}
+
+type sharedPair struct {
+	time, seq uint64
+}
+
+func parseSharedOutput(t *testing.T, out []byte) []sharedPair {
+	t.Helper()
+	var pairs []sharedPair
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		var p sharedPair
+		if _, err := fmt.Sscanf(scanner.Text(), "%d %d", &p.time, &p.seq); err != nil {
+			t.Fatalf("unexpected helper output %q: %v", scanner.Text(), err)
+		}
+		pairs = append(pairs, p)
+	}
+	return pairs
+}
+
+func newTestShared(t *testing.T, path string, seq *Sequence) *Shared {
+	t.Helper()
+	s, err := NewShared(path, seq)
+	if err != nil {
+		t.Fatalf("NewShared() returned error: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestNewShared(t *testing.T) {
+	t.Run("implements the sequencer interfaces", func(t *testing.T) {
+		var _ TrySequencer = &Shared{}
+		var _ interface{ Now() uint64 } = &Shared{}
+	})
+
+	t.Run("creates the state file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "monoton")
+		s := newTestShared(t, path, NewMillisecond())
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Stat() returned error: %v", err)
+		}
+		if info.Size() != sharedSize {
+			t.Errorf("state file size want: %d, got: %d", sharedSize, info.Size())
+		}
+		if s.MaxNode() != NewMillisecond().MaxNode() {
+			t.Errorf("MaxNode() want: %d, got: %d", NewMillisecond().MaxNode(), s.MaxNode())
+		}
+	})
+
+	t.Run("reinitializes stale files", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "monoton")
+		if err := os.WriteFile(path, []byte("stale"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		newTestShared(t, path, NewMillisecond())
+	})
+
+	t.Run("reinitializes files of other versions when unused", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "monoton")
+		clock := mtimer.NewFakeClock(time.Unix(5, 0))
+		s := newTestShared(t, path, NewMillisecond(WithClock(clock)))
+		s.Next()
+		s.store(offsetVersion, SharedVersion+1)
+		s.Close()
+
+		clock.Set(time.Unix(1, 0))
+		s = newTestShared(t, path, NewMillisecond(WithClock(clock)))
+		if got, _ := s.Next(); got != 1000 {
+			t.Errorf("Next().time want: 1000, got: %d", got)
+		}
+	})
+
+	t.Run("keeps the time of files with the same layout", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "monoton")
+		clock := mtimer.NewFakeClock(time.Unix(5, 0))
+		s := newTestShared(t, path, NewMillisecond(WithClock(clock)))
+		s.Next()
+		s.Close()
+
+		clock.Set(time.Unix(1, 0))
+		s = newTestShared(t, path, NewMillisecond(WithClock(clock)))
+		if got, seq := s.Next(); got != 5000 || seq != 1 {
+			t.Errorf("Next() want: 5000, 1, got: %d, %d", got, seq)
+		}
+	})
+
+	t.Run("errors on files of other versions in use", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "monoton")
+		s := newTestShared(t, path, NewMillisecond())
+		s.store(offsetVersion, SharedVersion+1)
+
+		_, err := NewShared(path, NewMillisecond())
+		var versionErr *SharedVersionError
+		if !errors.As(err, &versionErr) {
+			t.Fatalf("NewShared() want *SharedVersionError, got: %v", err)
+		}
+		if versionErr.Version != SharedVersion+1 || versionErr.Want != SharedVersion {
+			t.Errorf("unexpected version error: %v", versionErr)
+		}
+	})
+
+	t.Run("errors on files of other layouts in use", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "monoton")
+		newTestShared(t, path, NewMillisecond())
+
+		_, err := NewShared(path, NewMicrosecond())
+		var layoutErr *SharedLayoutError
+		if !errors.As(err, &layoutErr) || layoutErr.Path != path {
+			t.Errorf("NewShared() want *SharedLayoutError, got: %v", err)
+		}
+	})
+
+	t.Run("errors on large counters", func(t *testing.T) {
+		seq, err := NewCustom(Config{
+			Unit:         time.Second,
+			TimeBytes:    5,
+			CounterBytes: 9,
+			NodeBytes:    2,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = NewShared(filepath.Join(t.TempDir(), "monoton"), seq)
+		var counterErr *SharedCounterError
+		if !errors.As(err, &counterErr) || counterErr.Max != seq.Max() {
+			t.Errorf("NewShared() want *SharedCounterError, got: %v", err)
+		}
+	})
+
+	t.Run("errors on missing directories", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing", "monoton")
+		if _, err := NewShared(path, NewMillisecond()); err == nil {
+			t.Errorf("NewShared() should return error for missing directories")
+		}
+	})
+}
+
+func TestNext_Shared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "monoton")
+	clock := mtimer.NewFakeClock(time.Unix(1, 0))
+	a := newTestShared(t, path, NewMillisecond(WithClock(clock)))
+	b := newTestShared(t, path, NewMillisecond(WithClock(clock)))
+
+	t.Run("shares the counter of the time", func(t *testing.T) {
+		for i, s := range []*Shared{a, b, a, b} {
+			if got, seq := s.Next(); got != 1000 || seq != uint64(i) {
+				t.Errorf("Next() want: 1000, %d, got: %d, %d", i, got, seq)
+			}
+		}
+	})
+
+	t.Run("restarts the counter on the next time", func(t *testing.T) {
+		clock.Advance(time.Millisecond)
+		if got, seq := b.Next(); got != 1001 || seq != 0 {
+			t.Errorf("Next() want: 1001, 0, got: %d, %d", got, seq)
+		}
+		if got, seq := a.Next(); got != 1001 || seq != 1 {
+			t.Errorf("Next() want: 1001, 1, got: %d, %d", got, seq)
+		}
+	})
+
+	t.Run("never goes back in time", func(t *testing.T) {
+		behind := mtimer.NewFakeClock(time.Unix(0, 0))
+		c := newTestShared(t, path, NewMillisecond(WithClock(behind)))
+		if got, seq := c.Next(); got != 1001 || seq != 2 {
+			t.Errorf("Next() want: 1001, 2, got: %d, %d", got, seq)
+		}
+	})
+}
+
+func TestTryNext_Shared(t *testing.T) {
+	newSequence := func(clock mtimer.Clock, p OverflowPolicy) *Sequence {
+		seq, err := NewCustom(Config{
+			Unit:         time.Millisecond,
+			TimeBytes:    8,
+			CounterBytes: 1,
+			NodeBytes:    7,
+			Clock:        clock,
+		}, WithOverflowPolicy(p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return seq
+	}
+
+	t.Run("borrows the next time on overflows", func(t *testing.T) {
+		clock := mtimer.NewFakeClock(time.Unix(1, 0))
+		path := filepath.Join(t.TempDir(), "monoton")
+		s := newTestShared(t, path, newSequence(clock, OverflowBorrow))
+		for i := uint64(0); i <= s.Max(); i++ {
+			s.Next()
+		}
+		got, seq, err := s.TryNext()
+		if got != 1001 || seq != 0 || err != nil {
+			t.Errorf("TryNext() want: 1001, 0, <nil>, got: %d, %d, %v", got, seq, err)
+		}
+	})
+
+	t.Run("fails on overflows", func(t *testing.T) {
+		clock := mtimer.NewFakeClock(time.Unix(1, 0))
+		path := filepath.Join(t.TempDir(), "monoton")
+		s := newTestShared(t, path, newSequence(clock, OverflowFail))
+		for i := uint64(0); i <= s.Max(); i++ {
+			if _, _, err := s.TryNext(); err != nil {
+				t.Fatalf("TryNext() returned error: %v", err)
+			}
+		}
+		_, _, err := s.TryNext()
+		var overflowErr *OverflowError
+		if !errors.As(err, &overflowErr) || overflowErr.Time != 1000 {
+			t.Errorf("TryNext() want *OverflowError, got: %v", err)
+		}
+	})
+}
+
+func TestClose_Shared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "monoton")
+	s := newTestShared(t, path, NewMillisecond())
+	s.Next()
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+
+	t.Run("closes once", func(t *testing.T) {
+		if err := s.Close(); err != nil {
+			t.Errorf("Close() twice returned error: %v", err)
+		}
+	})
+
+	t.Run("errors on TryNext after close", func(t *testing.T) {
+		_, _, err := s.TryNext()
+		var closedErr *SharedClosedError
+		if !errors.As(err, &closedErr) || closedErr.Path != path {
+			t.Errorf("TryNext() want *SharedClosedError, got: %v", err)
+		}
+	})
+
+	t.Run("panics on Next after close", func(t *testing.T) {
+		defer func() {
+			want := "shared state " + path + " is closed"
+			if err, ok := recover().(error); !ok || err.Error() != want {
+				t.Errorf("Next() want panic: %s, got: %v", want, err)
+			}
+		}()
+		s.Next()
+	})
+}
+
+func TestShared_Processes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the multi-process test in short mode")
+	}
+	path := filepath.Join(t.TempDir(), "monoton")
+
+	t.Run("generates unique sequences across processes", func(t *testing.T) {
+		const processes, count = 4, 5000
+		cmds := make([]*exec.Cmd, processes)
+		outs := make([]*bytes.Buffer, processes)
+		for i := range cmds {
+			outs[i] = &bytes.Buffer{}
+			cmds[i] = runSharedHelper(path, count, false)
+			cmds[i].Stdout = outs[i]
+			cmds[i].Stderr = os.Stderr
+			if err := cmds[i].Start(); err != nil {
+				t.Fatalf("Start() returned error: %v", err)
+			}
+		}
+
+		seen := make(map[sharedPair]int, processes*count)
+		for i, cmd := range cmds {
+			if err := cmd.Wait(); err != nil {
+				t.Fatalf("helper process %d failed: %v", i, err)
+			}
+			pairs := parseSharedOutput(t, outs[i].Bytes())
+			if len(pairs) != count {
+				t.Fatalf("helper process %d want: %d, got: %d", i, count, len(pairs))
+			}
+			for j, p := range pairs {
+				if other, ok := seen[p]; ok {
+					t.Fatalf("sequence %v of process %d is also issued by %d", p, i, other)
+				}
+				seen[p] = i
+				if j == 0 {
+					continue
+				}
+				prev := pairs[j-1]
+				if p.time < prev.time || p.time == prev.time && p.seq <= prev.seq {
+					t.Fatalf("process %d issued %v after %v", i, p, prev)
+				}
+			}
+		}
+	})
+
+	t.Run("continues after crashed processes", func(t *testing.T) {
+		out, err := runSharedHelper(path, 100, true).Output()
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+			t.Fatalf("helper process want exit code 3, got: %v", err)
+		}
+		pairs := parseSharedOutput(t, out)
+		last := pairs[len(pairs)-1]
+
+		s := newTestShared(t, path, NewMillisecond())
+		got, seq := s.Next()
+		if got < last.time || got == last.time && seq <= last.seq {
+			t.Errorf("Next() want after %v, got: %d, %d", last, got, seq)
+		}
+	})
+}
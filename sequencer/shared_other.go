@@ -0,0 +1,37 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package sequencer
+
+import (
+	"errors"
+	"os"
+)
+
+var errSharedUnsupported = errors.New(
+	"shared sequencers are not supported on this platform",
+)
+
+func tryLockExclusive(file *os.File) (bool, error) {
+	return false, errSharedUnsupported
+}
+
+func lockShared(file *os.File) error {
+	return errSharedUnsupported
+}
+
+func unlockShared(file *os.File) error {
+	return errSharedUnsupported
+}
+
+func mapShared(file *os.File, size int) ([]byte, error) {
+	return nil, errSharedUnsupported
+}
+
+func unmapShared(mem []byte) error {
+	return errSharedUnsupported
+}
@@ -0,0 +1,129 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package sequencer
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// StateStore persists the high-water mark of the issued time values, so the
+// sequences of a restarted process never reuse the time values of the
+// previous process even if the clock steps backwards in between
+type StateStore interface {
+	// Load returns the persisted high-water mark, or 0 when there is none
+	Load() (uint64, error)
+	// Save persists the high-water mark
+	Save(uint64) error
+}
+
+// FileStore is a StateStore which keeps the high-water mark in a file
+type FileStore struct {
+	path string
+}
+
+// NewFileStore inits a file backed state store with the given file path
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Load reads the high-water mark from the file
+func (f *FileStore) Load() (uint64, error) {
+	data, err := os.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// Save writes the high-water mark into a temporary file and replaces the file
+// with it, so a crash while saving never leaves a partial value behind
+func (f *FileStore) Save(mark uint64) error {
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(strconv.FormatUint(mark, 10)); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+// Persist restores the high-water mark of the time values from the store and
+// keeps persisting it ahead of the issued time values. It must be called
+// before generating any sequence.
+//
+// The sequence doesn't issue the time values up to the restored mark. Until
+// the clock passes the mark, the overflow policy of the sequence is applied:
+// OverflowWait waits for the clock, OverflowBorrow continues from the mark
+// and OverflowFail refuses with TryNext.
+//
+// To avoid writing on each time change, the persisted mark is the issued time
+// value plus the ahead duration. TryNext returns the persistence errors, Next
+// and Reserve retry the failed saves until they succeed, so they block while
+// the store is failing.
+func (s *Sequence) Persist(store StateStore, ahead time.Duration) error {
+	mark, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.store = store
+	s.ahead = 1
+	if s.unit > 0 && ahead > s.unit {
+		s.ahead = uint64(ahead / s.unit)
+	}
+	atomic.StoreUint64(&s.saved, mark)
+
+	// The restored state is exhausted, so the next call has to move to a time
+	// value after the mark
+	if current := s.load(); current.time < mark {
+		restored := &state{time: mark, counter: s.max}
+		atomic.StorePointer(&s.state, unsafe.Pointer(restored))
+	}
+	return nil
+}
+
+// persist saves the high-water mark when the given time passes the persisted
+// one
+func (s *Sequence) persist(t uint64) error {
+	if s.store == nil || t <= atomic.LoadUint64(&s.saved) {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t <= s.saved {
+		return nil
+	}
+	mark := t + s.ahead
+	if err := s.store.Save(mark); err != nil {
+		return err
+	}
+	atomic.StoreUint64(&s.saved, mark)
+	return nil
+}
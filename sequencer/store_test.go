@@ -0,0 +1,193 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package sequencer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3/mtimer"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "monoton.state")
+	store := NewFileStore(path)
+
+	t.Run("loads zero without a file", func(t *testing.T) {
+		if got, err := store.Load(); got != 0 || err != nil {
+			t.Errorf("Load() want: 0, <nil>, got: %d, %v", got, err)
+		}
+	})
+
+	t.Run("loads the saved mark", func(t *testing.T) {
+		if err := store.Save(1234); err != nil {
+			t.Fatalf("Save() returned error: %v", err)
+		}
+		if got, err := store.Load(); got != 1234 || err != nil {
+			t.Errorf("Load() want: 1234, <nil>, got: %d, %v", got, err)
+		}
+	})
+
+	t.Run("errors on corrupt files", func(t *testing.T) {
+		if err := os.WriteFile(path, []byte("x"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.Load(); err == nil {
+			t.Errorf("Load() should return error for corrupt files")
+		}
+	})
+}
+
+func TestPersist_Sequence(t *testing.T) {
+	newSequence := func(clock *mtimer.FakeClock, p OverflowPolicy) *Sequence {
+		return NewMillisecond(WithClock(clock), WithOverflowPolicy(p))
+	}
+	store := &memoryStore{}
+
+	clock := mtimer.NewFakeClock(time.Unix(0, 100*int64(time.Millisecond)))
+	s := newSequence(clock, OverflowWait)
+	if err := s.Persist(store, 10*time.Millisecond); err != nil {
+		t.Fatalf("Persist() returned error: %v", err)
+	}
+
+	t.Run("saves the mark ahead of the time", func(t *testing.T) {
+		s.Next()
+		s.Next()
+		if store.mark != 110 || store.saves != 1 {
+			t.Errorf("Next() want mark: 110 saved once, got: %d, %d", store.mark, store.saves)
+		}
+
+		clock.Advance(5 * time.Millisecond)
+		s.Next()
+		if store.mark != 110 || store.saves != 1 {
+			t.Errorf("Next() before the mark want: 110, got: %d, %d", store.mark, store.saves)
+		}
+
+		clock.Advance(6 * time.Millisecond)
+		s.Next()
+		if store.mark != 121 || store.saves != 2 {
+			t.Errorf("Next() after the mark want: 121, got: %d, %d", store.mark, store.saves)
+		}
+	})
+
+	// The clock steps backwards before the restart
+	clock.Set(time.Unix(0, 50*int64(time.Millisecond)))
+
+	t.Run("refuses until the clock passes the mark", func(t *testing.T) {
+		s := newSequence(clock, OverflowFail)
+		if err := s.Persist(store, 10*time.Millisecond); err != nil {
+			t.Fatalf("Persist() returned error: %v", err)
+		}
+		want := "sequence exceeded the max value 14776335 for time 121"
+		if _, _, err := s.TryNext(); err == nil || err.Error() != want {
+			t.Errorf("TryNext() want error: %s, got: %v", want, err)
+		}
+	})
+
+	t.Run("borrows after the mark", func(t *testing.T) {
+		s := newSequence(clock, OverflowBorrow)
+		if err := s.Persist(store, 10*time.Millisecond); err != nil {
+			t.Fatalf("Persist() returned error: %v", err)
+		}
+		if got, seq := s.Next(); got != 122 || seq != 0 {
+			t.Errorf("Next() want: 122, 0, got: %d, %d", got, seq)
+		}
+	})
+
+	t.Run("waits for the clock", func(t *testing.T) {
+		s := newSequence(clock, OverflowWait)
+		if err := s.Persist(store, 10*time.Millisecond); err != nil {
+			t.Fatalf("Persist() returned error: %v", err)
+		}
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			clock.Set(time.Unix(0, 200*int64(time.Millisecond)))
+		}()
+		if got, seq := s.Next(); got != 200 || seq != 0 {
+			t.Errorf("Next() want: 200, 0, got: %d, %d", got, seq)
+		}
+	})
+}
+
+func TestPersist_Sequence_Errors(t *testing.T) {
+	want := errors.New("disk failure")
+
+	t.Run("returns load errors", func(t *testing.T) {
+		s := NewMillisecond()
+		if err := s.Persist(&memoryStore{err: want}, time.Second); err != want {
+			t.Errorf("Persist() want error: %v, got: %v", want, err)
+		}
+	})
+
+	t.Run("returns save errors on TryNext", func(t *testing.T) {
+		store := &memoryStore{}
+		s := NewMillisecond()
+		if err := s.Persist(store, time.Second); err != nil {
+			t.Fatalf("Persist() returned error: %v", err)
+		}
+		store.err = want
+		if _, _, err := s.TryNext(); err != want {
+			t.Errorf("TryNext() want error: %v, got: %v", want, err)
+		}
+	})
+
+	t.Run("retries save errors on Next", func(t *testing.T) {
+		clock := mtimer.NewFakeClock(time.Unix(0, 100*int64(time.Millisecond)))
+		store := &flakyStore{}
+		s := NewMillisecond(WithClock(clock))
+		if err := s.Persist(store, 10*time.Millisecond); err != nil {
+			t.Fatalf("Persist() returned error: %v", err)
+		}
+		store.failures = 2
+		if got, seq := s.Next(); got != 100 || seq != 0 {
+			t.Errorf("Next() want: 100, 0, got: %d, %d", got, seq)
+		}
+		if store.mark != 110 || store.saves != 1 || store.failures != 0 {
+			t.Errorf(
+				"Next() want mark: 110 saved after the failures, got: %d, %d, %d",
+				store.mark, store.saves, store.failures,
+			)
+		}
+	})
+}
+
+type memoryStore struct {
+	mark  uint64
+	saves int
+	err   error
+}
+
+func (m *memoryStore) Load() (uint64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	return m.mark, nil
+}
+
+func (m *memoryStore) Save(mark uint64) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.mark = mark
+	m.saves++
+	return nil
+}
+
+// flakyStore fails the given count of saves before saving
+type flakyStore struct {
+	memoryStore
+	failures int
+}
+
+func (f *flakyStore) Save(mark uint64) error {
+	if f.failures > 0 {
+		f.failures--
+		return errors.New("disk failure")
+	}
+	return f.memoryStore.Save(mark)
+}
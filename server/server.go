@@ -0,0 +1,229 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+/*
+Package server provides an HTTP handler which serves the identifiers of one
+monoton generator to the services which can't embed the Go package.
+
+The endpoints are:
+
+	GET /id            generates an identifier
+	GET /ids?n=100     generates n strictly ordered identifiers
+	GET /inspect/{id}  decodes an identifier into time, counter and node
+	GET /healthz       reports the liveness of the server
+
+The responses are plain text by default, and JSON when the request accepts
+application/json or has the format=json query parameter.
+
+	m, err := monoton.New(sequencer.NewMillisecond(), node, initialTime)
+	if err != nil {
+		panic(err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:8080")
+	if err != nil {
+		panic(err)
+	}
+	err = server.Serve(ctx, l, server.New(m), 5*time.Second)
+*/
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3"
+)
+
+const (
+	defaultMaxBatch = 1000
+	inspectPath     = "/inspect/"
+
+	errCount  = "n must be between 1 and %d (given %q)"
+	errMethod = "method %s is not allowed"
+)
+
+// Server is an http.Handler which serves the identifiers of a generator
+type Server struct {
+	monoton  monoton.Monoton
+	maxBatch int
+	mux      *http.ServeMux
+}
+
+// Option configures the optional behaviours of a Server
+type Option func(*Server)
+
+// WithMaxBatch sets the max number of identifiers which can be generated with
+// one /ids request, the default is 1000
+func WithMaxBatch(n int) Option {
+	return func(s *Server) {
+		s.maxBatch = n
+	}
+}
+
+// New returns a Server which generates the identifiers with the given monoton
+func New(m monoton.Monoton, opts ...Option) *Server {
+	s := &Server{monoton: m, maxBatch: defaultMaxBatch}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("/id", s.handleID)
+	s.mux.HandleFunc("/ids", s.handleIDs)
+	s.mux.HandleFunc(inspectPath, s.handleInspect)
+	s.mux.HandleFunc("/healthz", s.handleHealth)
+	return s
+}
+
+// Serve serves the handler on the listener until the context is done, then
+// shuts the server down gracefully by waiting the active requests up to the
+// timeout
+func Serve(
+	ctx context.Context,
+	l net.Listener,
+	h http.Handler,
+	timeout time.Duration,
+) error {
+	srv := &http.Server{Handler: h}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.Serve(l)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errs; err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// ServeHTTP implements the http.Handler interface
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, r, http.StatusMethodNotAllowed, fmt.Errorf(errMethod, r.Method))
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleID(w http.ResponseWriter, r *http.Request) {
+	id := s.monoton.NextBytes()
+	if wantsJSON(r) {
+		writeJSON(w, http.StatusOK, struct {
+			ID string `json:"id"`
+		}{string(id[:])})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write(append(id[:], '\n'))
+}
+
+func (s *Server) handleIDs(w http.ResponseWriter, r *http.Request) {
+	n := 1
+	if v := r.URL.Query().Get("n"); v != "" {
+		var err error
+		if n, err = strconv.Atoi(v); err != nil || n < 1 || n > s.maxBatch {
+			writeError(w, r, http.StatusBadRequest, fmt.Errorf(errCount, s.maxBatch, v))
+			return
+		}
+	}
+
+	ids := make([][16]byte, n)
+	s.monoton.NextBytesN(ids)
+	if wantsJSON(r) {
+		vals := make([]string, n)
+		for i := range ids {
+			vals[i] = string(ids[i][:])
+		}
+		writeJSON(w, http.StatusOK, struct {
+			IDs []string `json:"ids"`
+		}{vals})
+		return
+	}
+
+	buf := make([]byte, 0, n*(len(ids[0])+1))
+	for i := range ids {
+		buf = append(append(buf, ids[i][:]...), '\n')
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write(buf)
+}
+
+func (s *Server) handleInspect(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, inspectPath)
+	i, err := s.monoton.Inspect(id)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, http.StatusOK, i)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(
+		w,
+		"id: %s\ntime: %s\ncounter: %d\nnode: %d\n",
+		i.ID,
+		i.Time.Format(time.RFC3339Nano),
+		i.Counter,
+		i.Node,
+	)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if wantsJSON(r) {
+		writeJSON(w, http.StatusOK, struct {
+			Status string `json:"status"`
+		}{"ok"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte("ok\n"))
+}
+
+// wantsJSON reports whether the response of the request should be JSON
+func wantsJSON(r *http.Request) bool {
+	if f := r.URL.Query().Get("format"); f != "" {
+		return f == "json"
+	}
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if wantsJSON(r) {
+		writeJSON(w, status, struct {
+			Error string `json:"error"`
+		}{err.Error()})
+		return
+	}
+	http.Error(w, err.Error(), status)
+}
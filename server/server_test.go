@@ -0,0 +1,162 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3"
+	"github.com/mustafaturan/monoton/v3/mtimer"
+	"github.com/mustafaturan/monoton/v3/sequencer"
+)
+
+func TestServer(t *testing.T) {
+	clock := mtimer.NewFakeClock(time.Unix(1, 0))
+	s := sequencer.NewMillisecond(sequencer.WithClock(clock))
+	m, _ := monoton.New(s, 19, 0)
+	srv := httptest.NewServer(New(m, WithMaxBatch(3)))
+	defer srv.Close()
+
+	tests := []struct {
+		method     string
+		path       string
+		accept     string
+		wantStatus int
+		wantType   string
+		wantBody   string
+	}{
+		{"GET", "/id", "", 200, "text/plain; charset=utf-8", "000000G80000000J\n"},
+		{"GET", "/id?format=json", "", 200, "application/json", `{"id":"000000G80001000J"}` + "\n"},
+		{"GET", "/id", "application/json", 200, "application/json", `{"id":"000000G80002000J"}` + "\n"},
+		{"GET", "/ids?n=2", "", 200, "text/plain; charset=utf-8", "000000G80003000J\n000000G80004000J\n"},
+		{"GET", "/ids", "application/json", 200, "application/json", `{"ids":["000000G80005000J"]}` + "\n"},
+		{"GET", "/ids?n=4", "", 400, "text/plain; charset=utf-8", `n must be between 1 and 3 (given "4")` + "\n"},
+		{"GET", "/ids?n=x&format=json", "", 400, "application/json", `{"error":"n must be between 1 and 3 (given \"x\")"}` + "\n"},
+		{"GET", "/inspect/000000G80001000J", "", 200, "text/plain; charset=utf-8", "id: 000000G80001000J\ntime: 1970-01-01T00:00:01Z\ncounter: 1\nnode: 19\n"},
+		{"GET", "/inspect/000000G80001000J", "application/json", 200, "application/json", `{"id":"000000G80001000J","time":"1970-01-01T00:00:01Z","counter":1,"node":19}` + "\n"},
+		{"GET", "/inspect/000000G8000_000J", "", 400, "text/plain; charset=utf-8", "invalid char '_' at position 11\n"},
+		{"GET", "/healthz", "", 200, "text/plain; charset=utf-8", "ok\n"},
+		{"GET", "/healthz?format=json", "", 200, "application/json", `{"status":"ok"}` + "\n"},
+		{"POST", "/id", "", 405, "text/plain; charset=utf-8", "method POST is not allowed\n"},
+		{"GET", "/unknown", "", 404, "text/plain; charset=utf-8", "404 page not found\n"},
+	}
+
+	for _, test := range tests {
+		req, _ := http.NewRequest(test.method, srv.URL+test.path, nil)
+		if test.accept != "" {
+			req.Header.Set("Accept", test.accept)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s returned error: %v", test.method, test.path, err)
+		}
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+
+		if res.StatusCode != test.wantStatus ||
+			res.Header.Get("Content-Type") != test.wantType ||
+			string(body) != test.wantBody {
+			t.Errorf(
+				"%s %s want: %d, %s, %q, got: %d, %s, %q",
+				test.method, test.path,
+				test.wantStatus, test.wantType, test.wantBody,
+				res.StatusCode, res.Header.Get("Content-Type"), body,
+			)
+		}
+	}
+}
+
+func TestServerConcurrent(t *testing.T) {
+	m, _ := monoton.New(sequencer.NewMillisecond(), 19, 0)
+	h := New(m)
+
+	const workers, requests = 8, 50
+	ids := make(chan string, workers*requests)
+	done := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			for j := 0; j < requests; j++ {
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, httptest.NewRequest("GET", "/id?format=json", nil))
+				var res struct {
+					ID string `json:"id"`
+				}
+				json.NewDecoder(w.Body).Decode(&res)
+				ids <- res.ID
+			}
+		}()
+	}
+	for i := 0; i < workers; i++ {
+		<-done
+	}
+	close(ids)
+
+	seen := make(map[string]bool, workers*requests)
+	for id := range ids {
+		if len(id) != 16 || seen[id] {
+			t.Fatalf("/id returned an invalid or duplicate id: %q", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestServe(t *testing.T) {
+	m, _ := monoton.New(sequencer.NewMillisecond(), 19, 0)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() returned error: %v", err)
+	}
+
+	// the handler blocks until the shutdown starts to test the graceful close
+	started, release := make(chan struct{}), make(chan struct{})
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		New(m).ServeHTTP(w, r)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		errs <- Serve(ctx, l, h, time.Second)
+	}()
+
+	bodies := make(chan string, 1)
+	go func() {
+		res, err := http.Get("http://" + l.Addr().String() + "/healthz")
+		if err != nil {
+			bodies <- err.Error()
+			return
+		}
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		bodies <- string(body)
+	}()
+
+	<-started
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	if body := <-bodies; body != "ok\n" {
+		t.Errorf("GET /healthz during shutdown want: ok, got: %q", body)
+	}
+	if err := <-errs; err != nil {
+		t.Errorf("Serve() returned error: %v", err)
+	}
+	if _, err := http.Get("http://" + l.Addr().String() + "/healthz"); err == nil ||
+		!strings.Contains(err.Error(), "refused") {
+		t.Errorf("GET /healthz after shutdown want connection refused, got: %v", err)
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package monoton
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3/encoder"
+	"github.com/mustafaturan/monoton/v3/sequencer"
+)
+
+const (
+	ulidByteSize   = 26
+	ulidRandomBits = 80
+	unixTimeBits   = 48
+	unixMaxTime    = 1<<unixTimeBits - 1
+
+	errUnit       = "unit must be %s (given %s)"
+	errRandomBits = "counter(%d bits) and node(%d bits) can't exceed %d bits"
+	errULIDRange  = "ulid %q overflows 128 bits"
+)
+
+// UnitError is an error type with the unit of the sequencer and the unit
+// which is required
+type UnitError struct {
+	Unit     time.Duration
+	WantUnit time.Duration
+}
+
+func (e *UnitError) Error() string {
+	return fmt.Sprintf(errUnit, e.WantUnit, e.Unit)
+}
+
+// RandomBitsError is an error type with the bit sizes of the counter and the
+// node which don't fit into the random section
+type RandomBitsError struct {
+	CounterBits int
+	NodeBits    int
+	RandomBits  int
+}
+
+func (e *RandomBitsError) Error() string {
+	return fmt.Sprintf(errRandomBits, e.CounterBits, e.NodeBits, e.RandomBits)
+}
+
+// ULIDRangeError is an error type with the encoded ULID which can't be
+// represented with 128 bits
+type ULIDRangeError struct {
+	Value string
+}
+
+func (e *ULIDRangeError) Error() string {
+	return fmt.Sprintf(errULIDRange, e.Value)
+}
+
+// ULID is a 16 bytes identifier which is compatible with the ULID spec. It is
+// encoded as 26 chars Crockford Base32 text.
+type ULID [16]byte
+
+// ULIDGenerator is a sequential ULID generator. The 48 bits millisecond time
+// is followed by the counter and the node in the 80 bits random section, the
+// remaining low bits are zero.
+type ULIDGenerator struct {
+	sequencer   sequencer.Sequencer
+	node        uint64
+	counterBits int
+	nodeBits    int
+}
+
+// NewULIDGenerator inits a new ULID generator with the given millisecond
+// sequencer and node
+func NewULIDGenerator(
+	s sequencer.Sequencer,
+	node uint64,
+) (ULIDGenerator, error) {
+	if err := validateMillisecond(s, node); err != nil {
+		return ULIDGenerator{}, err
+	}
+
+	g := ULIDGenerator{
+		sequencer:   s,
+		node:        node,
+		counterBits: bits.Len64(s.Max()),
+		nodeBits:    bits.Len64(s.MaxNode()),
+	}
+	if g.counterBits+g.nodeBits > ulidRandomBits {
+		return ULIDGenerator{}, &RandomBitsError{
+			CounterBits: g.counterBits,
+			NodeBits:    g.nodeBits,
+			RandomBits:  ulidRandomBits,
+		}
+	}
+	return g, nil
+}
+
+// Next generates next incremental ULID
+func (g ULIDGenerator) Next() ULID {
+	t, seq := g.sequencer.Next()
+	return g.encode(t, seq)
+}
+
+// TryNext generates next incremental ULID like Next, but returns an error
+// instead of a corrupt identifier when the counter is exhausted or the time
+// exceeds 48 bits
+func (g ULIDGenerator) TryNext() (ULID, error) {
+	t, seq, err := tryNextMillisecond(g.sequencer)
+	if err != nil {
+		return ULID{}, err
+	}
+	return g.encode(t, seq), nil
+}
+
+// Parse decodes the given ULID into its components using the bit sizes of the
+// configured sequencer
+func (g ULIDGenerator) Parse(id ULID) Components {
+	hi, lo := id.uint128()
+	counterShift := uint(ulidRandomBits - g.counterBits)
+	nodeShift := counterShift - uint(g.nodeBits)
+	return Components{
+		Time:    hi >> (ulidRandomBits - 64),
+		Counter: mask(shr128(hi, lo, counterShift), g.counterBits),
+		Node:    mask(shr128(hi, lo, nodeShift), g.nodeBits),
+	}
+}
+
+func (g ULIDGenerator) encode(t, seq uint64) ULID {
+	counterShift := uint(ulidRandomBits - g.counterBits)
+	nodeShift := counterShift - uint(g.nodeBits)
+
+	hi, lo := shl128(t&unixMaxTime, ulidRandomBits)
+	chi, clo := shl128(seq, counterShift)
+	nhi, nlo := shl128(g.node, nodeShift)
+
+	var id ULID
+	binary.BigEndian.PutUint64(id[:8], hi|chi|nhi)
+	binary.BigEndian.PutUint64(id[8:], lo|clo|nlo)
+	return id
+}
+
+// ParseULID decodes the 26 chars Crockford Base32 text into a ULID, the lower
+// case chars are accepted
+func ParseULID(s string) (ULID, error) {
+	if len(s) != ulidByteSize {
+		return ULID{}, &InvalidByteSizeError{
+			ByteSize:      len(s),
+			ByteSizeTotal: ulidByteSize,
+		}
+	}
+
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		d, err := encoder.Base32.Decode([]byte{s[i]})
+		if err != nil {
+			return ULID{}, &encoder.InvalidCharError{Char: s[i], Position: i}
+		}
+		// 26 chars carry 130 bits, the first char can only use 3 bits
+		if i == 0 && d > 7 {
+			return ULID{}, &ULIDRangeError{Value: s}
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | d
+	}
+
+	var id ULID
+	binary.BigEndian.PutUint64(id[:8], hi)
+	binary.BigEndian.PutUint64(id[8:], lo)
+	return id, nil
+}
+
+// String returns the 26 chars Crockford Base32 representation of the ULID
+func (id ULID) String() string {
+	b, _ := id.MarshalText()
+	return string(b)
+}
+
+// Time returns the millisecond time of the ULID
+func (id ULID) Time() time.Time {
+	ms := binary.BigEndian.Uint64(id[:8]) >> (ulidRandomBits - 64)
+	return time.Unix(int64(ms/1000), int64(ms%1000)*int64(time.Millisecond))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface
+func (id ULID) MarshalText() ([]byte, error) {
+	hi, lo := id.uint128()
+	b := make([]byte, 0, ulidByteSize)
+	for i := ulidByteSize - 1; i >= 0; i-- {
+		b = encoder.Base32.AppendPadded(b, mask(shr128(hi, lo, uint(i*5)), 5), 1)
+	}
+	return b, nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface
+func (id *ULID) UnmarshalText(b []byte) error {
+	parsed, err := ParseULID(string(b))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// validateMillisecond validates the unit of the sequencer and the node for the
+// generators with the Unix millisecond time
+func validateMillisecond(s sequencer.Sequencer, node uint64) error {
+	if unit := s.Unit(); unit != time.Millisecond {
+		return &UnitError{Unit: unit, WantUnit: time.Millisecond}
+	}
+	if maxNode := s.MaxNode(); node > maxNode {
+		return &MaxNodeCapacityExceededError{Node: node, MaxNode: maxNode}
+	}
+	return nil
+}
+
+// tryNextMillisecond returns the next Unix millisecond time with the sequence
+// after validating that both of them fit into their bits
+func tryNextMillisecond(s sequencer.Sequencer) (uint64, uint64, error) {
+	var t, seq uint64
+	if ts, ok := s.(sequencer.TrySequencer); ok {
+		var err error
+		if t, seq, err = ts.TryNext(); err != nil {
+			return 0, 0, err
+		}
+	} else {
+		t, seq = s.Next()
+	}
+
+	if maxSeq := s.Max(); seq > maxSeq {
+		return 0, 0, &MaxSequenceExceededError{
+			Sequence:    seq,
+			MaxSequence: maxSeq,
+		}
+	}
+	if t > unixMaxTime {
+		return 0, 0, &MaxTimeExceededError{Time: t, MaxTime: unixMaxTime}
+	}
+	return t, seq, nil
+}
+
+func (id ULID) uint128() (uint64, uint64) {
+	return binary.BigEndian.Uint64(id[:8]), binary.BigEndian.Uint64(id[8:])
+}
+
+// shl128 shifts the value to left as a 128 bits integer
+func shl128(v uint64, shift uint) (uint64, uint64) {
+	if shift >= 64 {
+		return v << (shift - 64), 0
+	}
+	if shift == 0 {
+		return 0, v
+	}
+	return v >> (64 - shift), v << shift
+}
+
+// shr128 shifts the 128 bits integer to right and returns the low 64 bits
+func shr128(hi, lo uint64, shift uint) uint64 {
+	if shift >= 64 {
+		return hi >> (shift - 64)
+	}
+	if shift == 0 {
+		return lo
+	}
+	return lo>>shift | hi<<(64-shift)
+}
+
+func mask(v uint64, size int) uint64 {
+	if size >= 64 {
+		return v
+	}
+	return v & (1<<uint(size) - 1)
+}
@@ -0,0 +1,135 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package monoton
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3/mtimer"
+	"github.com/mustafaturan/monoton/v3/sequencer"
+)
+
+func TestNewULIDGenerator(t *testing.T) {
+	custom, _ := sequencer.NewCustom(sequencer.Config{
+		Unit:         time.Millisecond,
+		TimeBytes:    2,
+		CounterBytes: 6,
+		NodeBytes:    8,
+	})
+
+	tests := []struct {
+		s       sequencer.Sequencer
+		node    uint64
+		wantErr string
+	}{
+		{sequencer.NewSecond(), 1, "unit must be 1ms (given 1s)"},
+		{sequencer.NewMillisecond(), 14776336, "node can't be greater than 14776335 (given 14776336)"},
+		{custom, 1, "counter(36 bits) and node(48 bits) can't exceed 80 bits"},
+	}
+
+	for _, test := range tests {
+		_, err := NewULIDGenerator(test.s, test.node)
+		if err == nil || err.Error() != test.wantErr {
+			t.Errorf("NewULIDGenerator() want err: %s, got: %v", test.wantErr, err)
+		}
+	}
+}
+
+func TestULIDGenerator(t *testing.T) {
+	now := time.Unix(1469918176, int64(385*time.Millisecond))
+	clock := mtimer.NewFakeClock(now)
+	g, err := NewULIDGenerator(sequencer.NewMillisecond(sequencer.WithClock(clock)), 1000)
+	if err != nil {
+		t.Fatalf("NewULIDGenerator() returned error: %v", err)
+	}
+
+	id1, id2 := g.Next(), g.Next()
+	clock.Advance(time.Millisecond)
+	id3, err := g.TryNext()
+	if err != nil {
+		t.Fatalf("TryNext() returned error: %v", err)
+	}
+
+	ids := []ULID{id1, id2, id3}
+	want := []Components{
+		{Time: 1469918176385, Counter: 0, Node: 1000},
+		{Time: 1469918176385, Counter: 1, Node: 1000},
+		{Time: 1469918176386, Counter: 0, Node: 1000},
+	}
+	for i, id := range ids {
+		if got := g.Parse(id); got != want[i] {
+			t.Errorf("Parse(%s) want: %+v, got: %+v", id, want[i], got)
+		}
+		if got := id.String(); len(got) != 26 || !strings.HasPrefix(got, "01ARYZ6S4") {
+			t.Errorf("String() want a ULID with 01ARYZ6S4 prefix, got: %s", got)
+		}
+		if parsed, err := ParseULID(id.String()); err != nil || parsed != id {
+			t.Errorf("ParseULID(%s) got: %s, err: %v", id, parsed, err)
+		}
+		if i > 0 && ids[i-1].String() >= id.String() {
+			t.Errorf("Next(): %s >= Next(): %s", ids[i-1], id)
+		}
+	}
+
+	if got := id1.Time(); !got.Equal(now) {
+		t.Errorf("Time() want: %s, got: %s", now, got)
+	}
+
+	t.Run("returns the errors of the sequencer", func(t *testing.T) {
+		want := errors.New("exhausted")
+		g := ULIDGenerator{sequencer: &fixedSequencer{err: want}}
+		if _, err := g.TryNext(); err != want {
+			t.Errorf("TryNext() want error: %v, got: %v", want, err)
+		}
+	})
+}
+
+func TestParseULID(t *testing.T) {
+	tests := []string{
+		"01ARYZ6S41TSV4RRFFQ69G5FAV",
+		"00000000000000000000000000",
+		"7ZZZZZZZZZZZZZZZZZZZZZZZZZ",
+	}
+
+	for _, test := range tests {
+		id, err := ParseULID(test)
+		if err != nil || id.String() != test {
+			t.Errorf("ParseULID(%s) got: %s, err: %v", test, id, err)
+		}
+
+		lower, err := ParseULID(strings.ToLower(test))
+		if err != nil || lower != id {
+			t.Errorf("ParseULID(%s) got: %s, err: %v", strings.ToLower(test), lower, err)
+		}
+
+		var got ULID
+		if err := got.UnmarshalText([]byte(test)); err != nil || got != id {
+			t.Errorf("UnmarshalText(%s) got: %s, err: %v", test, got, err)
+		}
+	}
+
+	id, _ := ParseULID("01ARYZ6S41TSV4RRFFQ69G5FAV")
+	if got := id.Time().UnixNano() / int64(time.Millisecond); got != 1469918176385 {
+		t.Errorf("Time() want: 1469918176385, got: %d", got)
+	}
+
+	errorTests := []struct {
+		s       string
+		wantErr string
+	}{
+		{"01ARYZ6S41TSV4RRFFQ69G5FA", "byte size must be 26 (given 25)"},
+		{"01ARYZ6S41TSV4RRFFQ69G5FAU", "invalid char 'U' at position 25"},
+		{"80000000000000000000000000", `ulid "80000000000000000000000000" overflows 128 bits`},
+	}
+
+	for _, test := range errorTests {
+		if _, err := ParseULID(test.s); err == nil || err.Error() != test.wantErr {
+			t.Errorf("ParseULID(%s) want err: %s, got: %v", test.s, test.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package monoton
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/bits"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3/sequencer"
+)
+
+const (
+	uuidByteSize    = 36
+	uuidVersion     = 7
+	uuidVariant     = 2
+	uuidRandBBits   = 62
+	uuidPayloadBits = 12 + uuidRandBBits // rand_a + rand_b
+
+	errVersion = "uuid version must be %d (given %d)"
+	errUUID    = "invalid uuid %q"
+)
+
+// VersionError is an error type with the version of the UUID which is not
+// supported
+type VersionError struct {
+	Version     int
+	WantVersion int
+}
+
+func (e *VersionError) Error() string {
+	return fmt.Sprintf(errVersion, e.WantVersion, e.Version)
+}
+
+// InvalidUUIDError is an error type with the text which is not a canonical
+// UUID
+type InvalidUUIDError struct {
+	Value string
+}
+
+func (e *InvalidUUIDError) Error() string {
+	return fmt.Sprintf(errUUID, e.Value)
+}
+
+// UUID is a 16 bytes RFC 9562 identifier
+type UUID [16]byte
+
+// UUIDGenerator is a sequential UUIDv7 generator. The 48 bits Unix millisecond
+// time is followed by the counter in rand_a which spills into rand_b, and the
+// node with the optional randomness in rand_b.
+type UUIDGenerator struct {
+	sequencer   sequencer.Sequencer
+	node        uint64
+	counterBits int
+	nodeBits    int
+	random      io.Reader
+}
+
+// UUIDOption configures the optional behaviours of a UUIDGenerator
+type UUIDOption func(*UUIDGenerator)
+
+// WithRandomness fills the bits after the node with the bytes of the reader,
+// crypto/rand.Reader can be used as the source. The bits are zero when the
+// reader fails on Next, and TryNext returns the error of the reader.
+func WithRandomness(r io.Reader) UUIDOption {
+	return func(g *UUIDGenerator) {
+		g.random = r
+	}
+}
+
+// NewUUIDGenerator inits a new UUIDv7 generator with the given millisecond
+// sequencer and node
+func NewUUIDGenerator(
+	s sequencer.Sequencer,
+	node uint64,
+	opts ...UUIDOption,
+) (UUIDGenerator, error) {
+	if err := validateMillisecond(s, node); err != nil {
+		return UUIDGenerator{}, err
+	}
+
+	g := UUIDGenerator{
+		sequencer:   s,
+		node:        node,
+		counterBits: bits.Len64(s.Max()),
+		nodeBits:    bits.Len64(s.MaxNode()),
+	}
+	if g.counterBits+g.nodeBits > uuidPayloadBits {
+		return UUIDGenerator{}, &RandomBitsError{
+			CounterBits: g.counterBits,
+			NodeBits:    g.nodeBits,
+			RandomBits:  uuidPayloadBits,
+		}
+	}
+	for _, opt := range opts {
+		opt(&g)
+	}
+	return g, nil
+}
+
+// Next generates next incremental UUIDv7
+func (g UUIDGenerator) Next() UUID {
+	t, seq := g.sequencer.Next()
+	random, _ := g.randomBits()
+	return g.encode(t, seq, random)
+}
+
+// TryNext generates next incremental UUIDv7 like Next, but returns an error
+// instead of a corrupt identifier when the counter is exhausted, the time
+// exceeds 48 bits or the randomness can't be read
+func (g UUIDGenerator) TryNext() (UUID, error) {
+	t, seq, err := tryNextMillisecond(g.sequencer)
+	if err != nil {
+		return UUID{}, err
+	}
+	random, err := g.randomBits()
+	if err != nil {
+		return UUID{}, err
+	}
+	return g.encode(t, seq, random), nil
+}
+
+// Parse decodes the given UUIDv7 into its components using the bit sizes of
+// the configured sequencer
+func (g UUIDGenerator) Parse(id UUID) (Components, error) {
+	if v := id.Version(); v != uuidVersion {
+		return Components{}, &VersionError{
+			Version:     v,
+			WantVersion: uuidVersion,
+		}
+	}
+
+	hi, lo := binary.BigEndian.Uint64(id[:8]), binary.BigEndian.Uint64(id[8:])
+	randA, randB := hi&0xFFF, mask(lo, uuidRandBBits)
+	phi, plo := randA>>(64-uuidRandBBits), randA<<uuidRandBBits|randB
+
+	counterShift := uint(uuidPayloadBits - g.counterBits)
+	nodeShift := counterShift - uint(g.nodeBits)
+	return Components{
+		Time:    hi >> 16,
+		Counter: mask(shr128(phi, plo, counterShift), g.counterBits),
+		Node:    mask(shr128(phi, plo, nodeShift), g.nodeBits),
+	}, nil
+}
+
+// randomBits returns the random value for the bits after the node
+func (g UUIDGenerator) randomBits() (uint64, error) {
+	if g.random == nil {
+		return 0, nil
+	}
+	var b [8]byte
+	if _, err := io.ReadFull(g.random, b[:]); err != nil {
+		return 0, err
+	}
+	size := uuidPayloadBits - g.counterBits - g.nodeBits
+	return mask(binary.BigEndian.Uint64(b[:]), size), nil
+}
+
+func (g UUIDGenerator) encode(t, seq, random uint64) UUID {
+	counterShift := uint(uuidPayloadBits - g.counterBits)
+	nodeShift := counterShift - uint(g.nodeBits)
+
+	// payload is the 74 bits of rand_a and rand_b
+	chi, clo := shl128(seq, counterShift)
+	nhi, nlo := shl128(g.node, nodeShift)
+	phi, plo := chi|nhi, clo|nlo|random
+
+	randA := shr128(phi, plo, uuidRandBBits) & 0xFFF
+	randB := mask(plo, uuidRandBBits)
+
+	var id UUID
+	binary.BigEndian.PutUint64(
+		id[:8],
+		(t&unixMaxTime)<<16|uuidVersion<<12|randA,
+	)
+	binary.BigEndian.PutUint64(id[8:], uuidVariant<<uuidRandBBits|randB)
+	return id
+}
+
+// ParseUUID decodes the canonical 36 chars hex text into a UUID, the upper
+// case hex chars are accepted
+func ParseUUID(s string) (UUID, error) {
+	var id UUID
+	if len(s) != uuidByteSize {
+		return id, &InvalidByteSizeError{
+			ByteSize:      len(s),
+			ByteSizeTotal: uuidByteSize,
+		}
+	}
+	if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return id, &InvalidUUIDError{Value: s}
+	}
+
+	b := []byte(s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:])
+	if _, err := hex.Decode(id[:], b); err != nil {
+		return UUID{}, &InvalidUUIDError{Value: s}
+	}
+	return id, nil
+}
+
+// IsZero reports whether the id is the zero UUID
+func (id UUID) IsZero() bool {
+	return id == UUID{}
+}
+
+// Version returns the version of the UUID
+func (id UUID) Version() int {
+	return int(id[6] >> 4)
+}
+
+// Time returns the millisecond time of the UUIDv7
+func (id UUID) Time() time.Time {
+	ms := binary.BigEndian.Uint64(id[:8]) >> 16
+	return time.Unix(int64(ms/1000), int64(ms%1000)*int64(time.Millisecond))
+}
+
+// String returns the canonical 36 chars representation of the UUID
+func (id UUID) String() string {
+	b, _ := id.MarshalText()
+	return string(b)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface
+func (id UUID) MarshalText() ([]byte, error) {
+	b := make([]byte, uuidByteSize)
+	hex.Encode(b[0:8], id[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], id[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], id[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], id[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], id[10:])
+	return b, nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface
+func (id *UUID) UnmarshalText(b []byte) error {
+	parsed, err := ParseUUID(string(b))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// Scan implements the sql.Scanner interface, the canonical text and the raw
+// 16 bytes are accepted and NULL is scanned as the zero UUID
+func (id *UUID) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*id = UUID{}
+		return nil
+	case string:
+		return id.UnmarshalText([]byte(v))
+	case []byte:
+		if len(v) == len(id) {
+			copy(id[:], v)
+			return nil
+		}
+		return id.UnmarshalText(v)
+	default:
+		return &ScanTypeError{Value: src, Target: "UUID"}
+	}
+}
+
+// Value implements the driver.Valuer interface, the zero UUID is written as
+// NULL
+func (id UUID) Value() (driver.Value, error) {
+	if id.IsZero() {
+		return nil, nil
+	}
+	return id.String(), nil
+}
@@ -0,0 +1,169 @@
+// Copyright 2021 Mustafa Turan. All rights reserved.
+// Use of this source code is governed by a Apache License 2.0 license that can
+// be found in the LICENSE file.
+
+package monoton
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mustafaturan/monoton/v3/mtimer"
+	"github.com/mustafaturan/monoton/v3/sequencer"
+)
+
+func TestNewUUIDGenerator(t *testing.T) {
+	custom, _ := sequencer.NewCustom(sequencer.Config{
+		Unit:         time.Millisecond,
+		TimeBytes:    2,
+		CounterBytes: 6,
+		NodeBytes:    8,
+	})
+
+	tests := []struct {
+		s       sequencer.Sequencer
+		node    uint64
+		wantErr string
+	}{
+		{sequencer.NewNanosecond(), 1, "unit must be 1ms (given 1ns)"},
+		{sequencer.NewMillisecond(), 14776336, "node can't be greater than 14776335 (given 14776336)"},
+		{custom, 1, "counter(36 bits) and node(48 bits) can't exceed 74 bits"},
+	}
+
+	for _, test := range tests {
+		_, err := NewUUIDGenerator(test.s, test.node)
+		if err == nil || err.Error() != test.wantErr {
+			t.Errorf("NewUUIDGenerator() want err: %s, got: %v", test.wantErr, err)
+		}
+	}
+}
+
+func TestUUIDGenerator(t *testing.T) {
+	now := time.Unix(1645557742, 0)
+	clock := mtimer.NewFakeClock(now)
+	random := bytes.NewReader(bytes.Repeat([]byte{0xFF}, 16))
+	g, err := NewUUIDGenerator(
+		sequencer.NewMillisecond(sequencer.WithClock(clock)),
+		1000,
+		WithRandomness(random),
+	)
+	if err != nil {
+		t.Fatalf("NewUUIDGenerator() returned error: %v", err)
+	}
+
+	id1, id2 := g.Next(), g.Next()
+	clock.Advance(time.Millisecond)
+	id3 := g.Next()
+
+	ids := []UUID{id1, id2, id3}
+	want := []Components{
+		{Time: 1645557742000, Counter: 0, Node: 1000},
+		{Time: 1645557742000, Counter: 1, Node: 1000},
+		{Time: 1645557742001, Counter: 0, Node: 1000},
+	}
+	for i, id := range ids {
+		if got, err := g.Parse(id); err != nil || got != want[i] {
+			t.Errorf("Parse(%s) want: %+v, got: %+v, err: %v", id, want[i], got, err)
+		}
+		s := id.String()
+		if !strings.HasPrefix(s, "017f22e2-79b") || s[14] != '7' || !strings.ContainsAny(s[19:20], "89ab") {
+			t.Errorf("String() want a UUIDv7 with 017f22e2-79b prefix, got: %s", s)
+		}
+		if parsed, err := ParseUUID(s); err != nil || parsed != id {
+			t.Errorf("ParseUUID(%s) got: %s, err: %v", s, parsed, err)
+		}
+		if i > 0 && bytes.Compare(ids[i-1][:], id[:]) >= 0 {
+			t.Errorf("Next(): %s >= Next(): %s", ids[i-1], id)
+		}
+	}
+
+	if got := id1.Time(); !got.Equal(now) {
+		t.Errorf("Time() want: %s, got: %s", now, got)
+	}
+	// the remaining 26 bits of rand_b are filled with the randomness
+	if got := id1.String(); got != "017f22e2-79b0-7000-8000-000fa3ffffff" {
+		t.Errorf("Next() want: 017f22e2-79b0-7000-8000-000fa3ffffff, got: %s", got)
+	}
+
+	t.Run("returns the errors of the randomness", func(t *testing.T) {
+		if _, err := g.TryNext(); err != io.EOF {
+			t.Errorf("TryNext() want error: %v, got: %v", io.EOF, err)
+		}
+	})
+
+	t.Run("rejects the other versions", func(t *testing.T) {
+		id, _ := ParseUUID("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+		want := "uuid version must be 7 (given 1)"
+		if _, err := g.Parse(id); err == nil || err.Error() != want {
+			t.Errorf("Parse(%s) want err: %s, got: %v", id, want, err)
+		}
+	})
+}
+
+func TestParseUUID(t *testing.T) {
+	id, err := ParseUUID("017F22E2-79B0-7CC3-98C4-DC0C0C07398F")
+	if err != nil || id.String() != "017f22e2-79b0-7cc3-98c4-dc0c0c07398f" {
+		t.Errorf("ParseUUID() got: %s, err: %v", id, err)
+	}
+	if id.Version() != 7 {
+		t.Errorf("Version() want: 7, got: %d", id.Version())
+	}
+	if got := id.Time().UnixNano() / int64(time.Millisecond); got != 1645557742000 {
+		t.Errorf("Time() want: 1645557742000, got: %d", got)
+	}
+
+	errorTests := []struct {
+		s       string
+		wantErr string
+	}{
+		{"017f22e2-79b0-7cc3-98c4-dc0c0c07398", "byte size must be 36 (given 35)"},
+		{"017f22e2_79b0-7cc3-98c4-dc0c0c07398f", `invalid uuid "017f22e2_79b0-7cc3-98c4-dc0c0c07398f"`},
+		{"017f22e2-79b0-7cc3-98c4-dc0c0c07398g", `invalid uuid "017f22e2-79b0-7cc3-98c4-dc0c0c07398g"`},
+	}
+
+	for _, test := range errorTests {
+		if _, err := ParseUUID(test.s); err == nil || err.Error() != test.wantErr {
+			t.Errorf("ParseUUID(%s) want err: %s, got: %v", test.s, test.wantErr, err)
+		}
+	}
+}
+
+func TestUUIDSQL(t *testing.T) {
+	id, _ := ParseUUID("017f22e2-79b0-7cc3-98c4-dc0c0c07398f")
+
+	v, err := id.Value()
+	if err != nil || v != "017f22e2-79b0-7cc3-98c4-dc0c0c07398f" {
+		t.Errorf("Value() got: %v, err: %v", v, err)
+	}
+
+	srcs := []interface{}{
+		"017f22e2-79b0-7cc3-98c4-dc0c0c07398f",
+		[]byte("017f22e2-79b0-7cc3-98c4-dc0c0c07398f"),
+		id[:],
+	}
+	for _, src := range srcs {
+		var got UUID
+		if err := got.Scan(src); err != nil || got != id {
+			t.Errorf("Scan(%v) got: %s, err: %v", src, got, err)
+		}
+	}
+
+	t.Run("maps the zero UUID to NULL", func(t *testing.T) {
+		if v, err := (UUID{}).Value(); v != nil || err != nil {
+			t.Errorf("Value() want: <nil>, got: %v, err: %v", v, err)
+		}
+		got := id
+		if err := got.Scan(nil); err != nil || !got.IsZero() {
+			t.Errorf("Scan(nil) want the zero UUID, got: %s, err: %v", got, err)
+		}
+	})
+
+	var got UUID
+	want := "unsupported scan type int64 for UUID"
+	if err := got.Scan(int64(1)); err == nil || err.Error() != want {
+		t.Errorf("Scan(1) want err: %s, got: %v", want, err)
+	}
+}